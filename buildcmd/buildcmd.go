@@ -0,0 +1,117 @@
+package buildcmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/zhiruili/urem/core"
+	"github.com/zhiruili/urem/diag"
+	"github.com/zhiruili/urem/infocmd"
+	"github.com/zhiruili/urem/osutil"
+	"github.com/zhiruili/urem/unreal"
+)
+
+// Cmd 是用于通过 UBT 构建工程的命令。
+type Cmd struct {
+	Target        string   `arg:"-t,--target" help:"target to build, defaults to the editor target of the project"`
+	Platform      string   `arg:"-p,--platform" help:"target platform, defaults to the current platform"`
+	Configuration string   `arg:"-c,--config" help:"build configuration" default:"Development"`
+	UbtArgs       []string `arg:"--ubt-arg,separate" help:"extra argument passed to UBT, can be repeated"`
+	DiagFormat    string   `arg:"--diag-format" help:"export diagnostics of the build as json/quickfix/summary"`
+	DiagOutput    string   `arg:"--diag-out" help:"file to write the exported diagnostics, defaults to stdout"`
+	ProjectFile   string   `arg:"positional,required"`
+}
+
+func (cmd *Cmd) checkArgs() error {
+	if len(cmd.Platform) == 0 {
+		cmd.Platform = unreal.HostPlatform()
+	}
+
+	if !infocmd.IsLegalPlatform(cmd.Platform) {
+		return core.IllegalArgErrorf("Platform", "illegal value, must be oneof: %s",
+			infocmd.GetFmtAvailablePlatforms(", "))
+	}
+
+	if !infocmd.IsLegalConfiguration(cmd.Configuration) {
+		return core.IllegalArgErrorf("Configuration", "illegal value, must be oneof: %s",
+			infocmd.GetFmtAvailableConfigurations(", "))
+	}
+
+	if len(cmd.DiagFormat) != 0 && !core.StrContains(diag.AvailableFormats, cmd.DiagFormat) {
+		return core.IllegalArgErrorf("DiagFormat", "illegal value, must be oneof: %s",
+			strings.Join(diag.AvailableFormats, ", "))
+	}
+
+	return nil
+}
+
+func (cmd *Cmd) build(projectFilePath string) error {
+	projectInfo := &unreal.ProjectInfo{ProjectFilePath: projectFilePath}
+	target, err := projectInfo.ChooseTarget(cmd.Target)
+	if err != nil {
+		return err
+	}
+
+	info, err := unreal.FindProjectEngineInfo(projectInfo)
+	if err != nil {
+		return fmt.Errorf("find Unreal engine info: %w", err)
+	}
+
+	core.LogD("find Unreal engine info: %s %s", info.RealVersion(), info.InstallPath)
+
+	summary := fmt.Sprintf("%s %s %s", target, cmd.Platform, cmd.Configuration)
+	core.LogI("building %s with engine %s", summary, info.RealVersion())
+
+	args := []string{target, cmd.Platform, cmd.Configuration, "-project=" + projectFilePath, "-waitmutex"}
+	args = append(args, cmd.UbtArgs...)
+
+	begin := time.Now()
+	runner := unreal.NewUbtRunner(info.InstallPath, projectInfo)
+	err = runner.Run(args...)
+	elapsed := time.Since(begin).Round(time.Second)
+	if exportErr := cmd.exportDiagnostics(runner.Diagnostics()); exportErr != nil {
+		core.LogE("export diagnostics: %s", exportErr.Error())
+	}
+
+	if err != nil {
+		return fmt.Errorf("BUILD FAILED: %s after %s: %w", summary, elapsed, err)
+	}
+
+	core.LogI("BUILD SUCCEEDED: %s in %s", summary, elapsed)
+	return nil
+}
+
+// exportDiagnostics 按 --diag-format 指定的格式导出诊断信息。
+func (cmd *Cmd) exportDiagnostics(diagnostics []*diag.Diagnostic) error {
+	if len(cmd.DiagFormat) == 0 {
+		return nil
+	}
+
+	if len(cmd.DiagOutput) == 0 {
+		return diag.Write(os.Stdout, cmd.DiagFormat, diagnostics)
+	}
+
+	f, err := os.Create(cmd.DiagOutput)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := diag.Write(f, cmd.DiagFormat, diagnostics); err != nil {
+		return err
+	}
+
+	core.LogD("write diagnostics to %s", cmd.DiagOutput)
+	return f.Close()
+}
+
+// Run 执行构建操作。
+func (cmd *Cmd) Run() error {
+	if err := cmd.checkArgs(); err != nil {
+		return err
+	}
+
+	return osutil.DoInProjectRoot(cmd.ProjectFile, cmd.build)
+}
@@ -0,0 +1,138 @@
+package cleancmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/zhiruili/urem/core"
+	"github.com/zhiruili/urem/osutil"
+	"github.com/zhiruili/urem/unreal"
+)
+
+// 每个清理级别需要删除的目录。
+// Saved 中有 Config 的覆盖、自动保存和日志等用户数据，不属于任何级别，只有指定 --saved 时才会删除。
+var levelDirs = map[string][]string{
+	"intermediate": {"Intermediate"},
+	"binaries":     {"Binaries"},
+	"ddc":          {"DerivedDataCache"},
+	"all":          {"Binaries", "Intermediate", "DerivedDataCache"},
+}
+
+const savedDir = "Saved"
+
+var availableLevels = []string{"intermediate", "binaries", "ddc", "all"}
+
+// Cmd 是用于清理工程和插件生成的目录的命令。
+type Cmd struct {
+	Level       string `arg:"-l,--level" help:"clean level: intermediate (Intermediate), binaries (Binaries), ddc (DerivedDataCache), all (Binaries, Intermediate and DerivedDataCache)" default:"intermediate"`
+	Saved       bool   `arg:"--saved" help:"also delete Saved, which holds config overrides, autosaves and logs"`
+	DryRun      bool   `arg:"--dry-run" help:"only print the directories to delete"`
+	ProjectFile string `arg:"positional,required"`
+}
+
+type cleanTarget struct {
+	path string
+	size int64
+}
+
+// findCleanRoots 获取需要清理的根目录，包括工程目录和工程 Plugins 下每个插件的目录。
+func findCleanRoots(projectInfo *unreal.ProjectInfo) ([]string, error) {
+	roots := []string{projectInfo.ProjectDir()}
+	pluginFiles, err := unreal.FindPluginFiles(projectInfo.ProjectPluginsDir())
+	if err != nil {
+		return nil, fmt.Errorf("find plugins: %w", err)
+	}
+
+	for _, pluginFile := range pluginFiles {
+		roots = append(roots, filepath.Dir(pluginFile))
+	}
+
+	return roots, nil
+}
+
+func (cmd *Cmd) findTargets(projectInfo *unreal.ProjectInfo) ([]*cleanTarget, error) {
+	roots, err := findCleanRoots(projectInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	names := levelDirs[cmd.Level]
+	if cmd.Saved {
+		names = append(names[:len(names):len(names)], savedDir)
+	}
+
+	var targets []*cleanTarget
+	for _, root := range roots {
+		for _, name := range names {
+			p := filepath.Join(root, name)
+			if yes, err := osutil.IsDir(p); err != nil {
+				return nil, err
+			} else if !yes {
+				continue
+			}
+
+			size, err := osutil.DirSize(p)
+			if err != nil {
+				return nil, fmt.Errorf("calculate size of %s: %w", p, err)
+			}
+
+			targets = append(targets, &cleanTarget{path: p, size: size})
+		}
+	}
+
+	return targets, nil
+}
+
+func (cmd *Cmd) clean(projectFilePath string) error {
+	projectInfo := &unreal.ProjectInfo{ProjectFilePath: projectFilePath}
+	targets, err := cmd.findTargets(projectInfo)
+	if err != nil {
+		return err
+	}
+
+	if len(targets) == 0 {
+		core.LogI("nothing to clean")
+		return nil
+	}
+
+	var total int64
+	for _, target := range targets {
+		rel, err := filepath.Rel(projectInfo.ProjectDir(), target.path)
+		if err != nil {
+			rel = target.path
+		}
+
+		core.LogI("%10s  %s", osutil.FormatSize(target.size), rel)
+		total += target.size
+	}
+
+	core.LogI("%10s  total of %d directories", osutil.FormatSize(total), len(targets))
+	if cmd.DryRun {
+		return nil
+	}
+
+	if !core.GetUserBoolInput("Delete these directories?") {
+		return fmt.Errorf("user cancel")
+	}
+
+	for _, target := range targets {
+		core.LogD("remove %s", target.path)
+		if err := os.RemoveAll(target.path); err != nil {
+			return fmt.Errorf("remove %s: %w", target.path, err)
+		}
+	}
+
+	core.LogI("%s cleaned", osutil.FormatSize(total))
+	return nil
+}
+
+// Run 执行清理操作。
+func (cmd *Cmd) Run() error {
+	if _, ok := levelDirs[cmd.Level]; !ok {
+		return core.IllegalArgErrorf("Level", "illegal value, must be oneof: %s", strings.Join(availableLevels, ", "))
+	}
+
+	return osutil.DoInProjectRoot(cmd.ProjectFile, cmd.clean)
+}
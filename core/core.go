@@ -14,6 +14,7 @@ type Args struct {
 	Quite     bool   `arg:"-q,--quite" help:"don't ask for user input"`
 	Verbose   bool   `arg:"-v,--verbose" help:"increase log verbosity level"`
 	PProfFile string `arg:"--pprof" help:"dump profiling info to file"`
+	Engine    string `arg:"--engine" help:"root dir of the engine to use, override the engine associated with the project"`
 }
 
 type globalData struct {
@@ -0,0 +1,19 @@
+package depscmd
+
+import (
+	"fmt"
+)
+
+// Cmd 是 deps 子命令的集合。
+type Cmd struct {
+	GraphCommand *DepsGraphCmd `arg:"subcommand:graph" help:"render module dependency graph of the project."`
+}
+
+// Run 实现了 subCmd 的接口。
+func (cmd *Cmd) Run() error {
+	if cmd.GraphCommand != nil {
+		return cmd.GraphCommand.Run()
+	}
+
+	return fmt.Errorf("missing subcommand of deps cmd")
+}
@@ -0,0 +1,323 @@
+package depscmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/zhiruili/urem/modulerules"
+)
+
+// 依赖的类型。
+const (
+	edgePublic  = "public"  // PublicDependencyModuleNames
+	edgePrivate = "private" // PrivateDependencyModuleNames
+	edgeDynamic = "dynamic" // DynamicallyLoadedModuleNames
+)
+
+// edgeKindOrder 用于合并同一对 module 之间的多条依赖，值越小越优先。
+var edgeKindOrder = map[string]int{edgePublic: 0, edgePrivate: 1, edgeDynamic: 2}
+
+// moduleNameRegexp 匹配合法的 module 名字。
+var moduleNameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// moduleInfo 是工程中的一个 module 以及解析出的规则。
+type moduleInfo struct {
+	plugin string // 所属的工程插件，为空时属于工程
+	rules  *modulerules.ModuleRules
+}
+
+type node struct {
+	Name   string `json:"name"`
+	Plugin string `json:"plugin,omitempty"`
+	Engine bool   `json:"engine,omitempty"` // 不在工程中的 module，包括引擎和引擎插件中的 module
+}
+
+type edge struct {
+	From        string `json:"from"`
+	To          string `json:"to"`
+	Kind        string `json:"kind"`
+	Conditional bool   `json:"conditional,omitempty"` // 依赖是否在无法静态求值的条件中
+	Condition   string `json:"condition,omitempty"`
+}
+
+// graph 是 module 的依赖图。
+type graph struct {
+	Name  string  `json:"name"`
+	Nodes []*node `json:"nodes"`
+	Edges []*edge `json:"edges"`
+}
+
+// buildGraph 根据 module 的规则创建依赖图，只保留在 platform 上生效的依赖。
+// 同一对 module 之间有多条依赖时只保留一条，public 优先于 private，private 优先于 dynamic。
+func buildGraph(name string, modules []*moduleInfo, platform string) *graph {
+	g := &graph{Name: name, Nodes: []*node{}, Edges: []*edge{}}
+	nodes := map[string]*node{}
+	for _, m := range modules {
+		if _, ok := nodes[m.rules.Name]; !ok {
+			nodes[m.rules.Name] = &node{Name: m.rules.Name, Plugin: m.plugin}
+		}
+	}
+
+	edges := map[[2]string]*edge{}
+	addEdges := func(from string, items []*modulerules.Item, kind string) {
+		for _, item := range items {
+			// 无法静态求值的依赖不会出现在图中
+			if item.Expression || !item.AppliesTo(platform) || !moduleNameRegexp.MatchString(item.Value) || item.Value == from {
+				continue
+			}
+
+			if _, ok := nodes[item.Value]; !ok {
+				nodes[item.Value] = &node{Name: item.Value, Engine: true}
+			}
+
+			e := &edge{From: from, To: item.Value, Kind: kind, Conditional: item.Conditional, Condition: item.Condition}
+			key := [2]string{from, item.Value}
+			old, ok := edges[key]
+			if !ok || edgeKindOrder[kind] < edgeKindOrder[old.Kind] ||
+				(edgeKindOrder[kind] == edgeKindOrder[old.Kind] && old.Conditional && !e.Conditional) {
+				edges[key] = e
+			}
+		}
+	}
+
+	for _, m := range modules {
+		addEdges(m.rules.Name, m.rules.PublicDependencyModuleNames, edgePublic)
+		addEdges(m.rules.Name, m.rules.PrivateDependencyModuleNames, edgePrivate)
+		addEdges(m.rules.Name, m.rules.DynamicallyLoadedModuleNames, edgeDynamic)
+	}
+
+	for _, n := range nodes {
+		g.Nodes = append(g.Nodes, n)
+	}
+
+	for _, e := range edges {
+		g.Edges = append(g.Edges, e)
+	}
+
+	g.sort()
+	return g
+}
+
+func (g *graph) sort() {
+	sort.Slice(g.Nodes, func(i, j int) bool { return g.Nodes[i].Name < g.Nodes[j].Name })
+	sort.Slice(g.Edges, func(i, j int) bool {
+		if g.Edges[i].From != g.Edges[j].From {
+			return g.Edges[i].From < g.Edges[j].From
+		}
+		return g.Edges[i].To < g.Edges[j].To
+	})
+}
+
+// keep 只保留 keepNode 返回 true 的节点和 keepEdge 返回 true 的边，边的两端都必须被保留。
+func (g *graph) keep(keepNode func(n *node) bool, keepEdge func(e *edge) bool) {
+	kept := map[string]bool{}
+	nodes := g.Nodes[:0]
+	for _, n := range g.Nodes {
+		if keepNode(n) {
+			nodes = append(nodes, n)
+			kept[n.Name] = true
+		}
+	}
+
+	edges := g.Edges[:0]
+	for _, e := range g.Edges {
+		if kept[e.From] && kept[e.To] && keepEdge(e) {
+			edges = append(edges, e)
+		}
+	}
+
+	g.Nodes, g.Edges = nodes, edges
+}
+
+// filterKinds 只保留指定类型的依赖。
+func (g *graph) filterKinds(kinds []string) {
+	g.keep(func(n *node) bool { return true }, func(e *edge) bool {
+		for _, kind := range kinds {
+			if e.Kind == kind {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// hideEngine 去掉不在工程中的 module。
+func (g *graph) hideEngine() {
+	g.keep(func(n *node) bool { return !n.Engine }, func(e *edge) bool { return true })
+}
+
+// focus 只保留从 name 出发可以到达的 module，即 name 直接和间接依赖的所有 module。
+func (g *graph) focus(name string) error {
+	deps := map[string][]string{}
+	found := false
+	for _, n := range g.Nodes {
+		if n.Name == name {
+			found = true
+		}
+	}
+
+	if !found {
+		return fmt.Errorf("module %s not found in the graph", name)
+	}
+
+	for _, e := range g.Edges {
+		deps[e.From] = append(deps[e.From], e.To)
+	}
+
+	reached := map[string]bool{name: true}
+	queue := []string{name}
+	for len(queue) != 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, dep := range deps[cur] {
+			if !reached[dep] {
+				reached[dep] = true
+				queue = append(queue, dep)
+			}
+		}
+	}
+
+	g.keep(func(n *node) bool { return reached[n.Name] }, func(e *edge) bool { return true })
+	return nil
+}
+
+// pluginGroups 将工程插件中的 module 按插件分组，返回排序后的插件名和每个插件中的 module。
+func (g *graph) pluginGroups() ([]string, map[string][]*node) {
+	groups := map[string][]*node{}
+	for _, n := range g.Nodes {
+		if len(n.Plugin) != 0 {
+			groups[n.Plugin] = append(groups[n.Plugin], n)
+		}
+	}
+
+	var plugins []string
+	for plugin := range groups {
+		plugins = append(plugins, plugin)
+	}
+
+	sort.Strings(plugins)
+	return plugins, groups
+}
+
+func quoteDot(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
+
+// writeDot 以 Graphviz DOT 格式输出依赖图。public 依赖是实线，private 依赖是虚线，dynamic 依赖是点线，
+// 条件依赖是灰色，工程外的 module 是灰色背景，插件中的 module 放在以插件命名的子图中。
+func writeDot(w io.Writer, g *graph) error {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "digraph %s {\n", quoteDot(g.Name))
+	sb.WriteString("\trankdir=LR;\n")
+	sb.WriteString("\tnode [shape=box];\n")
+	for _, n := range g.Nodes {
+		if n.Engine {
+			fmt.Fprintf(&sb, "\t%s [style=filled, fillcolor=lightgray];\n", quoteDot(n.Name))
+		} else if len(n.Plugin) == 0 {
+			fmt.Fprintf(&sb, "\t%s;\n", quoteDot(n.Name))
+		}
+	}
+
+	plugins, groups := g.pluginGroups()
+	for _, plugin := range plugins {
+		fmt.Fprintf(&sb, "\tsubgraph %s {\n", quoteDot("cluster_"+plugin))
+		fmt.Fprintf(&sb, "\t\tlabel=%s;\n", quoteDot(plugin))
+		for _, n := range groups[plugin] {
+			fmt.Fprintf(&sb, "\t\t%s;\n", quoteDot(n.Name))
+		}
+		sb.WriteString("\t}\n")
+	}
+
+	for _, e := range g.Edges {
+		var attrs []string
+		switch e.Kind {
+		case edgePrivate:
+			attrs = append(attrs, "style=dashed")
+		case edgeDynamic:
+			attrs = append(attrs, "style=dotted")
+		}
+
+		if e.Conditional {
+			attrs = append(attrs, "color=gray", "tooltip="+quoteDot(e.Condition))
+		}
+
+		if len(attrs) == 0 {
+			fmt.Fprintf(&sb, "\t%s -> %s;\n", quoteDot(e.From), quoteDot(e.To))
+		} else {
+			fmt.Fprintf(&sb, "\t%s -> %s [%s];\n", quoteDot(e.From), quoteDot(e.To), strings.Join(attrs, ", "))
+		}
+	}
+
+	sb.WriteString("}\n")
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// mermaidId 获取 module 在 Mermaid 中的 id，加上前缀避免和 end 等关键字冲突。
+func mermaidId(name string) string {
+	return "m_" + name
+}
+
+// writeMermaid 以 Mermaid flowchart 格式输出依赖图。public 依赖是实线，private 和 dynamic 依赖是虚线，
+// dynamic 和条件依赖会带上标签，工程外的 module 使用 engine 样式。
+func writeMermaid(w io.Writer, g *graph) error {
+	var sb strings.Builder
+	sb.WriteString("graph LR\n")
+	sb.WriteString("\tclassDef engine fill:#eee,stroke:#999,color:#666\n")
+	for _, n := range g.Nodes {
+		if len(n.Plugin) != 0 {
+			continue
+		}
+
+		fmt.Fprintf(&sb, "\t%s[\"%s\"]", mermaidId(n.Name), n.Name)
+		if n.Engine {
+			sb.WriteString(":::engine")
+		}
+		sb.WriteString("\n")
+	}
+
+	plugins, groups := g.pluginGroups()
+	for _, plugin := range plugins {
+		fmt.Fprintf(&sb, "\tsubgraph p_%s[\"%s\"]\n", plugin, plugin)
+		for _, n := range groups[plugin] {
+			fmt.Fprintf(&sb, "\t\t%s[\"%s\"]\n", mermaidId(n.Name), n.Name)
+		}
+		sb.WriteString("\tend\n")
+	}
+
+	for _, e := range g.Edges {
+		arrow := "-->"
+		if e.Kind != edgePublic {
+			arrow = "-.->"
+		}
+
+		var labels []string
+		if e.Kind == edgeDynamic {
+			labels = append(labels, edgeDynamic)
+		}
+		if e.Conditional {
+			labels = append(labels, "conditional")
+		}
+
+		label := ""
+		if len(labels) != 0 {
+			label = "|" + strings.Join(labels, ", ") + "|"
+		}
+
+		fmt.Fprintf(&sb, "\t%s %s%s %s\n", mermaidId(e.From), arrow, label, mermaidId(e.To))
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func writeJSON(w io.Writer, g *graph) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(g)
+}
@@ -0,0 +1,189 @@
+package depscmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/zhiruili/urem/modulerules"
+)
+
+func testModules() []*moduleInfo {
+	game := modulerules.Parse(`public class Game : ModuleRules {
+	public Game(ReadOnlyTargetRules Target) : base(Target) {
+		PublicDependencyModuleNames.AddRange(new string[] { "Core", "Engine", "Lib" });
+		PrivateDependencyModuleNames.AddRange(new string[] { "Core", "Slate" });
+		if (Target.Platform == UnrealTargetPlatform.Win64) { PrivateDependencyModuleNames.Add("D3D12RHI"); }
+		if (Target.bBuildEditor) { PrivateDependencyModuleNames.Add("UnrealEd"); }
+		DynamicallyLoadedModuleNames.AddRange(ExtraModules);
+	}
+}`)
+	lib := modulerules.Parse(`public class Lib : ModuleRules {
+	public Lib(ReadOnlyTargetRules Target) : base(Target) {
+		PublicDependencyModuleNames.Add("Core");
+		DynamicallyLoadedModuleNames.Add("Json");
+	}
+}`)
+	tool := modulerules.Parse(`public class Tool : ModuleRules {
+	public Tool(ReadOnlyTargetRules Target) : base(Target) {
+		PrivateDependencyModuleNames.Add("Game");
+	}
+}`)
+	return []*moduleInfo{{rules: game}, {plugin: "LibPlugin", rules: lib}, {rules: tool}}
+}
+
+func edgeStrings(g *graph) []string {
+	var edges []string
+	for _, e := range g.Edges {
+		s := e.From + "->" + e.To + ":" + e.Kind
+		if e.Conditional {
+			s += "?"
+		}
+		edges = append(edges, s)
+	}
+
+	return edges
+}
+
+func nodeStrings(g *graph) []string {
+	var nodes []string
+	for _, n := range g.Nodes {
+		nodes = append(nodes, n.Name)
+	}
+
+	return nodes
+}
+
+// TestGraphFilter 测试创建依赖图以及过滤。
+func TestGraphFilter(t *testing.T) {
+	cases := []struct {
+		name        string
+		platform    string
+		kinds       []string
+		hideEngine  bool
+		focus       string
+		expectNodes []string
+		expectEdges []string
+	}{
+		{
+			name:        "all",
+			platform:    "Linux",
+			expectNodes: []string{"Core", "Engine", "Game", "Json", "Lib", "Slate", "Tool", "UnrealEd"},
+			expectEdges: []string{"Game->Core:public", "Game->Engine:public", "Game->Lib:public",
+				"Game->Slate:private", "Game->UnrealEd:private?", "Lib->Core:public", "Lib->Json:dynamic",
+				"Tool->Game:private"},
+		},
+		{
+			name:        "platform",
+			platform:    "Win64",
+			kinds:       []string{edgePrivate},
+			expectNodes: []string{"Core", "D3D12RHI", "Engine", "Game", "Json", "Lib", "Slate", "Tool", "UnrealEd"},
+			expectEdges: []string{"Game->D3D12RHI:private", "Game->Slate:private", "Game->UnrealEd:private?",
+				"Tool->Game:private"},
+		},
+		{
+			name:        "hide engine",
+			platform:    "Linux",
+			hideEngine:  true,
+			expectNodes: []string{"Game", "Lib", "Tool"},
+			expectEdges: []string{"Game->Lib:public", "Tool->Game:private"},
+		},
+		{
+			name:        "focus",
+			platform:    "Linux",
+			focus:       "Lib",
+			expectNodes: []string{"Core", "Json", "Lib"},
+			expectEdges: []string{"Lib->Core:public", "Lib->Json:dynamic"},
+		},
+		{
+			name:        "focus public",
+			platform:    "Linux",
+			kinds:       []string{edgePublic},
+			focus:       "Tool",
+			expectNodes: []string{"Tool"},
+		},
+	}
+
+	for i, c := range cases {
+		g := buildGraph("Game", testModules(), c.platform)
+		if len(c.kinds) != 0 {
+			g.filterKinds(c.kinds)
+		}
+
+		if c.hideEngine {
+			g.hideEngine()
+		}
+
+		if len(c.focus) != 0 {
+			if err := g.focus(c.focus); err != nil {
+				t.Errorf("%d:%s: %s", i, c.name, err)
+				continue
+			}
+		}
+
+		if nodes := nodeStrings(g); !reflect.DeepEqual(nodes, c.expectNodes) {
+			t.Errorf("%d:%s: expect nodes %v, actual %v", i, c.name, c.expectNodes, nodes)
+		}
+
+		if edges := edgeStrings(g); !reflect.DeepEqual(edges, c.expectEdges) {
+			t.Errorf("%d:%s: expect edges %v, actual %v", i, c.name, c.expectEdges, edges)
+		}
+	}
+
+	if err := buildGraph("Game", testModules(), "Linux").focus("Nope"); err == nil {
+		t.Errorf("expect error when focus on unknown module")
+	}
+}
+
+// TestWriteGraph 测试输出依赖图。
+func TestWriteGraph(t *testing.T) {
+	g := buildGraph("Game", testModules(), "Linux")
+	g.focus("Lib")
+
+	cases := []struct {
+		name   string
+		write  func(sb *strings.Builder, g *graph) error
+		expect string
+	}{
+		{
+			name:  "dot",
+			write: func(sb *strings.Builder, g *graph) error { return writeDot(sb, g) },
+			expect: `digraph "Game" {
+	rankdir=LR;
+	node [shape=box];
+	"Core" [style=filled, fillcolor=lightgray];
+	"Json" [style=filled, fillcolor=lightgray];
+	subgraph "cluster_LibPlugin" {
+		label="LibPlugin";
+		"Lib";
+	}
+	"Lib" -> "Core";
+	"Lib" -> "Json" [style=dotted];
+}
+`,
+		},
+		{
+			name:  "mermaid",
+			write: func(sb *strings.Builder, g *graph) error { return writeMermaid(sb, g) },
+			expect: `graph LR
+	classDef engine fill:#eee,stroke:#999,color:#666
+	m_Core["Core"]:::engine
+	m_Json["Json"]:::engine
+	subgraph p_LibPlugin["LibPlugin"]
+		m_Lib["Lib"]
+	end
+	m_Lib --> m_Core
+	m_Lib -.->|dynamic| m_Json
+`,
+		},
+	}
+
+	for i, c := range cases {
+		var sb strings.Builder
+		if err := c.write(&sb, g); err != nil {
+			t.Errorf("%d:%s: %s", i, c.name, err)
+		} else if sb.String() != c.expect {
+			t.Errorf("%d:%s: expect:\n%s\nactual:\n%s", i, c.name, c.expect, sb.String())
+		}
+	}
+}
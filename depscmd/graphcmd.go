@@ -0,0 +1,169 @@
+package depscmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/zhiruili/urem/core"
+	"github.com/zhiruili/urem/descriptor"
+	"github.com/zhiruili/urem/infocmd"
+	"github.com/zhiruili/urem/modulerules"
+	"github.com/zhiruili/urem/osutil"
+	"github.com/zhiruili/urem/unreal"
+)
+
+// 支持的输出格式。
+const (
+	formatDot     = "dot"
+	formatMermaid = "mermaid"
+	formatJSON    = "json"
+)
+
+var availableFormats = []string{formatDot, formatMermaid, formatJSON}
+
+const edgesAll = "all"
+
+var availableEdges = []string{edgesAll, edgePublic, edgePrivate, edgeDynamic}
+
+// DepsGraphCmd 是用于输出 module 依赖图的子命令。
+type DepsGraphCmd struct {
+	Format      string `arg:"-f,--format" help:"output format dot/mermaid/json" default:"dot"`
+	HideEngine  bool   `arg:"--hide-engine" help:"hide modules not in the project or project plugins"`
+	Focus       string `arg:"--focus" help:"only show the module and its transitive dependencies"`
+	Edges       string `arg:"--edges" help:"kind of dependencies to show all/public/private/dynamic" default:"all"`
+	Platform    string `arg:"-p,--platform" help:"target platform, defaults to the current platform"`
+	Output      string `arg:"-o,--output" help:"file to write the graph, defaults to stdout"`
+	ProjectFile string `arg:"positional" default:"."`
+}
+
+func (cmd *DepsGraphCmd) checkArgs() error {
+	if !core.StrContains(availableFormats, cmd.Format) {
+		return core.IllegalArgErrorf("Format", "illegal value, must be oneof: %s", strings.Join(availableFormats, ", "))
+	}
+
+	if !core.StrContains(availableEdges, cmd.Edges) {
+		return core.IllegalArgErrorf("Edges", "illegal value, must be oneof: %s", strings.Join(availableEdges, ", "))
+	}
+
+	if len(cmd.Platform) == 0 {
+		cmd.Platform = unreal.HostPlatform()
+	}
+
+	if !infocmd.IsLegalPlatform(cmd.Platform) {
+		return core.IllegalArgErrorf("Platform", "illegal value, must be oneof: %s",
+			infocmd.GetFmtAvailablePlatforms(", "))
+	}
+
+	return nil
+}
+
+// addModules 解析 dir 下所有的 *.Build.cs，解析失败的 module 会被跳过。
+func addModules(modules []*moduleInfo, dir string, plugin string) ([]*moduleInfo, error) {
+	files, err := unreal.FindModuleFiles(dir)
+	if err != nil {
+		return nil, fmt.Errorf("find modules: %w", err)
+	}
+
+	for _, file := range files {
+		rules, err := modulerules.ParseFile(file)
+		if err != nil {
+			core.LogE("warning: %s", err.Error())
+			continue
+		}
+
+		core.LogD("found module %s: %s", rules.Name, file)
+		modules = append(modules, &moduleInfo{plugin: plugin, rules: rules})
+	}
+
+	return modules, nil
+}
+
+// collectModules 查找工程和工程插件中的所有 module。
+func collectModules(projectInfo *unreal.ProjectInfo) ([]*moduleInfo, error) {
+	project, err := descriptor.LoadProject(projectInfo.ProjectFilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	modules, err := addModules(nil, projectInfo.ProjectSourceDir(), "")
+	if err != nil {
+		return nil, err
+	}
+
+	pluginFiles, err := projectInfo.FindProjectPluginFiles(project.AdditionalPluginDirectories)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file := range pluginFiles {
+		modules, err = addModules(modules, filepath.Join(filepath.Dir(file), "Source"), unreal.PluginNameOfFile(file))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return modules, nil
+}
+
+func (cmd *DepsGraphCmd) writeGraph(w io.Writer, g *graph) error {
+	switch cmd.Format {
+	case formatJSON:
+		return writeJSON(w, g)
+	case formatMermaid:
+		return writeMermaid(w, g)
+	default:
+		return writeDot(w, g)
+	}
+}
+
+func (cmd *DepsGraphCmd) renderGraph(projectFilePath string) error {
+	projectInfo := &unreal.ProjectInfo{ProjectFilePath: projectFilePath}
+	modules, err := collectModules(projectInfo)
+	if err != nil {
+		return err
+	}
+
+	g := buildGraph(projectInfo.ProjectName(), modules, cmd.Platform)
+	if cmd.Edges != edgesAll {
+		g.filterKinds([]string{cmd.Edges})
+	}
+
+	if cmd.HideEngine {
+		g.hideEngine()
+	}
+
+	if len(cmd.Focus) != 0 {
+		if err := g.focus(cmd.Focus); err != nil {
+			return core.IllegalArgErrorf("Focus", "%s", err.Error())
+		}
+	}
+
+	if len(cmd.Output) == 0 {
+		return cmd.writeGraph(os.Stdout, g)
+	}
+
+	f, err := os.Create(cmd.Output)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := cmd.writeGraph(f, g); err != nil {
+		return err
+	}
+
+	core.LogD("write dependency graph to %s", cmd.Output)
+	return f.Close()
+}
+
+// Run 执行依赖图输出逻辑。
+func (cmd *DepsGraphCmd) Run() error {
+	if err := cmd.checkArgs(); err != nil {
+		return err
+	}
+
+	return osutil.DoInProjectRoot(cmd.ProjectFile, cmd.renderGraph)
+}
@@ -0,0 +1,274 @@
+// Package descriptor 提供 .uproject 和 .uplugin 描述文件的类型化模型。
+// 修改描述文件时会保留未知的字段、字段的顺序、缩进、换行符和文件末尾的换行，
+// 只有被修改的字段会被重新写入，参考 UE 的实现：
+// FProjectDescriptor::Read / FProjectDescriptor::Write
+// FPluginDescriptor::Read / FPluginDescriptor::Write
+package descriptor
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/iancoleman/orderedmap"
+)
+
+var utf8Bom = []byte{0xEF, 0xBB, 0xBF}
+
+// format 记录描述文件原有的格式，写回时保持一致。
+type format struct {
+	indent          string // 缩进，UE 生成的文件使用 tab
+	crlf            bool   // 是否使用 \r\n 换行
+	trailingNewline bool   // 文件末尾是否有换行
+	bom             bool   // 是否有 UTF-8 BOM
+	content         []byte // 原有的内容，写回时只替换修改过的值，为空时生成完整的内容
+}
+
+// detectFormat 检测描述文件的格式，检测不到缩进时使用 tab。
+func detectFormat(content []byte) format {
+	f := format{
+		indent:          "\t",
+		crlf:            bytes.Contains(content, []byte("\r\n")),
+		trailingNewline: bytes.HasSuffix(content, []byte("\n")),
+		bom:             bytes.HasPrefix(content, utf8Bom),
+		content:         content,
+	}
+
+	for _, line := range bytes.Split(content, []byte("\n")) {
+		trimmed := bytes.TrimLeft(line, " \t")
+		if len(trimmed) != len(line) && len(bytes.TrimSpace(trimmed)) != 0 {
+			f.indent = string(line[:len(line)-len(trimmed)])
+			break
+		}
+	}
+
+	return f
+}
+
+// rawHolder 是保存了原始 JSON 对象的类型，用于在写回时保留未知字段和字段顺序。
+type rawHolder interface {
+	rawObject() *orderedmap.OrderedMap
+	setRawObject(raw *orderedmap.OrderedMap)
+}
+
+// newObject 创建一个空的 JSON 对象，和解析得到的对象一样不转义 HTML 字符。
+func newObject() *orderedmap.OrderedMap {
+	var o orderedmap.OrderedMap
+	_ = json.Unmarshal([]byte("{}"), &o)
+	return &o
+}
+
+// parse 解析描述文件，v 是类型化的模型，返回原始的 JSON 对象和文件格式。
+func parse(content []byte, v interface{}) (*orderedmap.OrderedMap, format, error) {
+	f := detectFormat(content)
+	content = bytes.TrimPrefix(content, utf8Bom)
+
+	var raw orderedmap.OrderedMap
+	if err := json.Unmarshal(content, &raw); err != nil {
+		return nil, f, fmt.Errorf("unmarshal descriptor: %w", err)
+	}
+
+	if err := json.Unmarshal(content, v); err != nil {
+		return nil, f, fmt.Errorf("unmarshal descriptor: %w", err)
+	}
+
+	return &raw, f, nil
+}
+
+// marshal 将 v 中的字段写回原始的 JSON 对象，并按照原有的格式输出，isNew 表示 raw 是新建的对象。
+// 有原有的内容时只替换和插入修改过的值，其余内容保持不变。
+func marshal(raw *orderedmap.OrderedMap, v interface{}, f format, isNew bool) ([]byte, error) {
+	if err := syncObject(raw, reflect.ValueOf(v).Elem(), isNew); err != nil {
+		return nil, err
+	}
+
+	if len(f.content) != 0 {
+		return splice(f.content, f, raw)
+	}
+
+	compact, err := raw.MarshalJSON()
+	if err != nil {
+		return nil, fmt.Errorf("marshal descriptor: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if f.bom {
+		buf.Write(utf8Bom)
+	}
+
+	if err := json.Indent(&buf, compact, "", f.indent); err != nil {
+		return nil, fmt.Errorf("indent descriptor: %w", err)
+	}
+
+	if f.trailingNewline {
+		buf.WriteString("\n")
+	}
+
+	out := buf.Bytes()
+	if f.crlf {
+		// JSON 字符串中的换行都被转义了，所以可以直接替换
+		out = bytes.ReplaceAll(out, []byte("\n"), []byte("\r\n"))
+	}
+
+	return out, nil
+}
+
+// attachRawObjects 将原始 JSON 对象中 key 对应的对象数组关联到类型化的元素上。
+func attachRawObjects(raw *orderedmap.OrderedMap, key string, holders []rawHolder) {
+	value, ok := raw.Get(key)
+	if !ok {
+		return
+	}
+
+	items, ok := value.([]interface{})
+	if !ok || len(items) != len(holders) {
+		return
+	}
+
+	for i, item := range items {
+		if obj, ok := item.(orderedmap.OrderedMap); ok && holders[i] != nil {
+			holders[i].setRawObject(&obj)
+		}
+	}
+}
+
+// fieldInfo 获取结构体字段对应的 JSON key 以及是否有 omitempty 标记。
+func fieldInfo(f reflect.StructField) (string, bool, bool) {
+	if f.PkgPath != "" {
+		return "", false, false
+	}
+
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return "", false, false
+	}
+
+	parts := strings.Split(tag, ",")
+	name := parts[0]
+	if len(name) == 0 {
+		name = f.Name
+	}
+
+	omitEmpty := false
+	for _, opt := range parts[1:] {
+		if opt == "omitempty" {
+			omitEmpty = true
+		}
+	}
+
+	return name, omitEmpty, true
+}
+
+// syncObject 将结构体 v 的字段写入 raw。和原有值相同的字段不做修改，新增的字段插入到结构体中前一个已存在的字段之后。
+// 不存在的零值字段不会被写入，除非 raw 是新建的对象并且字段没有 omitempty 标记。
+func syncObject(raw *orderedmap.OrderedMap, v reflect.Value, isNew bool) error {
+	t := v.Type()
+	prevKey := ""
+	for i := 0; i < t.NumField(); i++ {
+		name, omitEmpty, ok := fieldInfo(t.Field(i))
+		if !ok {
+			continue
+		}
+
+		fv := v.Field(i)
+		old, existed := raw.Get(name)
+		if !existed && fv.IsZero() && (!isNew || omitEmpty) {
+			continue
+		}
+
+		value, err := toRawValue(fv)
+		if err != nil {
+			return fmt.Errorf("marshal field %s: %w", name, err)
+		}
+
+		if !existed || !reflect.DeepEqual(old, value) {
+			raw.Set(name, value)
+		}
+
+		if !existed {
+			moveKeyAfter(raw, name, prevKey)
+		}
+
+		prevKey = name
+	}
+
+	return nil
+}
+
+// moveKeyAfter 将 key 移动到 prevKey 之后，prevKey 为空时移动到最前面。
+func moveKeyAfter(raw *orderedmap.OrderedMap, key string, prevKey string) {
+	raw.SortKeys(func(keys []string) {
+		from := -1
+		to := 0
+		for i, k := range keys {
+			if k == key {
+				from = i
+			} else if k == prevKey {
+				to = i + 1
+			}
+		}
+
+		if from < 0 {
+			return
+		}
+
+		if from < to {
+			to--
+		}
+
+		copy(keys[from:], keys[from+1:])
+		copy(keys[to+1:], keys[to:len(keys)-1])
+		keys[to] = key
+	})
+}
+
+// toRawValue 将字段的值转换为 orderedmap 中使用的值。
+func toRawValue(fv reflect.Value) (interface{}, error) {
+	if holder, ok := fv.Interface().(rawHolder); ok {
+		if fv.IsNil() {
+			return nil, nil
+		}
+
+		raw := holder.rawObject()
+		isNew := raw == nil
+		if isNew {
+			raw = newObject()
+			holder.setRawObject(raw)
+		}
+
+		if err := syncObject(raw, fv.Elem(), isNew); err != nil {
+			return nil, err
+		}
+
+		return raw, nil
+	}
+
+	if fv.Kind() == reflect.Slice {
+		items := make([]interface{}, 0, fv.Len())
+		for i := 0; i < fv.Len(); i++ {
+			item, err := toRawValue(fv.Index(i))
+			if err != nil {
+				return nil, err
+			}
+
+			items = append(items, item)
+		}
+
+		return items, nil
+	}
+
+	// 其他值通过 JSON 转换为 float64、string、bool 等基础类型，以便和原有的值比较
+	bs, err := json.Marshal(fv.Interface())
+	if err != nil {
+		return nil, err
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(bs, &value); err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
@@ -0,0 +1,244 @@
+package descriptor
+
+import (
+	"testing"
+)
+
+// TestProjectRoundTrip 测试不做修改时 .uproject 文件的内容保持不变。
+func TestProjectRoundTrip(t *testing.T) {
+	cases := []struct {
+		name string
+		json string
+	}{
+		{
+			name: "ue format",
+			json: "{\n\t\"FileVersion\": 3,\n\t\"EngineAssociation\": \"5.3\",\n\t\"Category\": \"\",\n\t\"Description\": \"\",\n" +
+				"\t\"Modules\": [\n\t\t{\n\t\t\t\"Name\": \"Game\",\n\t\t\t\"Type\": \"Runtime\",\n\t\t\t\"LoadingPhase\": \"Default\",\n" +
+				"\t\t\t\"AdditionalDependencies\": [\n\t\t\t\t\"Engine\"\n\t\t\t],\n\t\t\t\"CustomField\": 1.5\n\t\t}\n\t],\n" +
+				"\t\"Plugins\": [\n\t\t{\n\t\t\t\"Name\": \"ModelingToolsEditorMode\",\n\t\t\t\"Enabled\": false,\n" +
+				"\t\t\t\"TargetAllowList\": [\n\t\t\t\t\"Editor\"\n\t\t\t]\n\t\t}\n\t],\n" +
+				"\t\"PostBuildSteps\": {\n\t\t\"Win64\": [\n\t\t\t\"echo <done>\"\n\t\t]\n\t}\n}\n",
+		},
+		{
+			name: "crlf without trailing newline",
+			json: "{\r\n  \"EngineAssociation\": \"{4A1D5C2E-6F5B-4A34-8D5B-1B2C3D4E5F60}\",\r\n  \"FileVersion\": 3,\r\n  \"Modules\": []\r\n}",
+		},
+		{
+			name: "bom",
+			json: "\xEF\xBB\xBF{\n\t\"FileVersion\": 3\n}\n",
+		},
+	}
+
+	for i, c := range cases {
+		d, err := ParseProject([]byte(c.json))
+		if err != nil {
+			t.Errorf("%d:%s: unexpected error: %s", i, c.name, err)
+			continue
+		}
+
+		actual, err := d.Marshal()
+		if err != nil {
+			t.Errorf("%d:%s: unexpected error: %s", i, c.name, err)
+			continue
+		}
+
+		if c.json != string(actual) {
+			t.Errorf("%d:%s:\nexpect:\n%s\n\nactual:\n%s", i, c.name, c.json, actual)
+		}
+	}
+}
+
+// TestProjectEdit 测试修改 .uproject 文件时保留未知字段和字段顺序。
+func TestProjectEdit(t *testing.T) {
+	cases := []struct {
+		name   string
+		json   string
+		edit   func(d *ProjectDescriptor)
+		expect string
+	}{
+		{
+			name: "replace engine association",
+			json: `{
+	"FileVersion": 3,
+	"EngineAssociation": "5.1",
+	"Category": ""
+}`,
+			edit: func(d *ProjectDescriptor) { d.EngineAssociation = "5.3" },
+			expect: `{
+	"FileVersion": 3,
+	"EngineAssociation": "5.3",
+	"Category": ""
+}`,
+		},
+		{
+			name: "ignore nested key",
+			json: `{
+	"Plugins": [
+		{
+			"Name": "A",
+			"Enabled": true,
+			"EngineAssociation": "nested"
+		}
+	],
+	"EngineAssociation": "",
+	"Modules": []
+}`,
+			edit: func(d *ProjectDescriptor) { d.EngineAssociation = "5.3" },
+			expect: `{
+	"Plugins": [
+		{
+			"Name": "A",
+			"Enabled": true,
+			"EngineAssociation": "nested"
+		}
+	],
+	"EngineAssociation": "5.3",
+	"Modules": []
+}`,
+		},
+		{
+			name: "insert missing key after previous field",
+			json: `{
+  "FileVersion": 3,
+  "Unknown": true
+}`,
+			edit: func(d *ProjectDescriptor) { d.EngineAssociation = "5.3" },
+			expect: `{
+  "FileVersion": 3,
+  "EngineAssociation": "5.3",
+  "Unknown": true
+}`,
+		},
+		{
+			name: "insert into empty object",
+			json: `{}`,
+			edit: func(d *ProjectDescriptor) { d.EngineAssociation = "5.3" },
+			expect: `{
+	"EngineAssociation": "5.3"
+}`,
+		},
+		{
+			name: "edit module and add plugin",
+			json: `{
+	"FileVersion": 3,
+	"Modules": [
+		{
+			"Name": "Game",
+			"Type": "Runtime",
+			"Custom": "keep"
+		}
+	],
+	"TargetPlatforms": [
+		"Win64"
+	]
+}`,
+			edit: func(d *ProjectDescriptor) {
+				d.FindModule("Game").LoadingPhase = "PreDefault"
+				d.Plugins = append(d.Plugins, &PluginReference{Name: "Tools", Enabled: false})
+				d.TargetPlatforms = append(d.TargetPlatforms, "Linux")
+			},
+			expect: `{
+	"FileVersion": 3,
+	"Modules": [
+		{
+			"Name": "Game",
+			"Type": "Runtime",
+			"LoadingPhase": "PreDefault",
+			"Custom": "keep"
+		}
+	],
+	"Plugins": [
+		{
+			"Name": "Tools",
+			"Enabled": false
+		}
+	],
+	"TargetPlatforms": [
+		"Win64",
+		"Linux"
+	]
+}`,
+		},
+		{
+			name: "keep untouched content",
+			json: `{
+	"FileVersion":3,
+	"EngineAssociation" : "5.1",
+	"Modules": [
+		{ "Name": "Game", "Type": "Runtime" }
+	],
+	"Plugins": [{"Name": "A", "Enabled": true}],
+	"Scale": 1.0,
+	"PostBuildSteps": {"Win64": ["echo <done>"]}
+}`,
+			edit: func(d *ProjectDescriptor) {
+				d.EngineAssociation = "5.3"
+				d.Modules = append([]*ModuleDescriptor{{Name: "Tools", Type: "Editor", LoadingPhase: "Default"}}, d.Modules...)
+			},
+			expect: `{
+	"FileVersion":3,
+	"EngineAssociation" : "5.3",
+	"Modules": [
+		{
+			"Name": "Tools",
+			"Type": "Editor",
+			"LoadingPhase": "Default"
+		},
+		{ "Name": "Game", "Type": "Runtime" }
+	],
+	"Plugins": [{"Name": "A", "Enabled": true}],
+	"Scale": 1.0,
+	"PostBuildSteps": {"Win64": ["echo <done>"]}
+}`,
+		},
+	}
+
+	for i, c := range cases {
+		d, err := ParseProject([]byte(c.json))
+		if err != nil {
+			t.Errorf("%d:%s: unexpected error: %s", i, c.name, err)
+			continue
+		}
+
+		c.edit(d)
+		actual, err := d.Marshal()
+		if err != nil {
+			t.Errorf("%d:%s: unexpected error: %s", i, c.name, err)
+			continue
+		}
+
+		if c.expect != string(actual) {
+			t.Errorf("%d:%s:\nexpect:\n%s\n\nactual:\n%s", i, c.name, c.expect, actual)
+		}
+	}
+}
+
+// TestPluginEdit 测试修改 .uplugin 文件。
+func TestPluginEdit(t *testing.T) {
+	json := "{\r\n\t\"FileVersion\": 3,\r\n\t\"Version\": 1,\r\n\t\"VersionName\": \"1.0\",\r\n\t\"FriendlyName\": \"Tools\",\r\n" +
+		"\t\"CanContainContent\": true,\r\n\t\"Modules\": [\r\n\t\t{\r\n\t\t\t\"Name\": \"Tools\",\r\n\t\t\t\"Type\": \"Editor\",\r\n" +
+		"\t\t\t\"LoadingPhase\": \"Default\",\r\n\t\t\t\"WhitelistPlatforms\": [\r\n\t\t\t\t\"Win64\"\r\n\t\t\t]\r\n\t\t}\r\n\t]\r\n}\r\n"
+	expect := "{\r\n\t\"FileVersion\": 3,\r\n\t\"Version\": 2,\r\n\t\"VersionName\": \"1.0\",\r\n\t\"FriendlyName\": \"Tools\",\r\n" +
+		"\t\"CanContainContent\": true,\r\n\t\"Modules\": [\r\n\t\t{\r\n\t\t\t\"Name\": \"Tools\",\r\n\t\t\t\"Type\": \"Editor\",\r\n" +
+		"\t\t\t\"LoadingPhase\": \"Default\",\r\n\t\t\t\"WhitelistPlatforms\": [\r\n\t\t\t\t\"Win64\",\r\n\t\t\t\t\"Linux\"\r\n\t\t\t]\r\n\t\t}\r\n\t]\r\n}\r\n"
+
+	d, err := ParsePlugin([]byte(json))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if d.FriendlyName != "Tools" || !d.CanContainContent || len(d.Modules) != 1 {
+		t.Fatalf("unexpected descriptor: %+v", d)
+	}
+
+	d.Version = 2
+	d.Modules[0].WhitelistPlatforms = append(d.Modules[0].WhitelistPlatforms, "Linux")
+	actual, err := d.Marshal()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if expect != string(actual) {
+		t.Errorf("expect:\n%s\n\nactual:\n%s", expect, actual)
+	}
+}
@@ -0,0 +1,118 @@
+package descriptor
+
+import "github.com/iancoleman/orderedmap"
+
+// ModuleDescriptor 是描述文件中 Modules 的一项，参考 UE 的实现：
+// FModuleDescriptor::Read / FModuleDescriptor::Write
+type ModuleDescriptor struct {
+	Name                          string
+	Type                          string
+	LoadingPhase                  string   `json:",omitempty"`
+	PlatformAllowList             []string `json:",omitempty"`
+	PlatformDenyList              []string `json:",omitempty"`
+	TargetAllowList               []string `json:",omitempty"`
+	TargetDenyList                []string `json:",omitempty"`
+	TargetConfigurationAllowList  []string `json:",omitempty"`
+	TargetConfigurationDenyList   []string `json:",omitempty"`
+	ProgramAllowList              []string `json:",omitempty"`
+	ProgramDenyList               []string `json:",omitempty"`
+	AdditionalDependencies        []string `json:",omitempty"`
+	HasExplicitPlatforms          bool     `json:",omitempty"`
+	WhitelistPlatforms            []string `json:",omitempty"` // UE5.1 之前的 PlatformAllowList
+	BlacklistPlatforms            []string `json:",omitempty"` // UE5.1 之前的 PlatformDenyList
+	WhitelistTargets              []string `json:",omitempty"`
+	BlacklistTargets              []string `json:",omitempty"`
+	WhitelistTargetConfigurations []string `json:",omitempty"`
+	BlacklistTargetConfigurations []string `json:",omitempty"`
+	WhitelistPrograms             []string `json:",omitempty"`
+	BlacklistPrograms             []string `json:",omitempty"`
+
+	raw *orderedmap.OrderedMap
+}
+
+func (m *ModuleDescriptor) rawObject() *orderedmap.OrderedMap {
+	return m.raw
+}
+
+func (m *ModuleDescriptor) setRawObject(raw *orderedmap.OrderedMap) {
+	m.raw = raw
+}
+
+// PluginReference 是描述文件中 Plugins 的一项，表示对插件的引用，参考 UE 的实现：
+// FPluginReferenceDescriptor::Read / FPluginReferenceDescriptor::Write
+type PluginReference struct {
+	Name                          string
+	Enabled                       bool
+	Optional                      bool     `json:",omitempty"`
+	Description                   string   `json:",omitempty"`
+	MarketplaceURL                string   `json:",omitempty"`
+	PlatformAllowList             []string `json:",omitempty"`
+	PlatformDenyList              []string `json:",omitempty"`
+	TargetConfigurationAllowList  []string `json:",omitempty"`
+	TargetConfigurationDenyList   []string `json:",omitempty"`
+	TargetAllowList               []string `json:",omitempty"`
+	TargetDenyList                []string `json:",omitempty"`
+	SupportedTargetPlatforms      []string `json:",omitempty"`
+	HasExplicitPlatforms          bool     `json:",omitempty"`
+	WhitelistPlatforms            []string `json:",omitempty"` // UE5.1 之前的 PlatformAllowList
+	BlacklistPlatforms            []string `json:",omitempty"` // UE5.1 之前的 PlatformDenyList
+	WhitelistTargetConfigurations []string `json:",omitempty"`
+	BlacklistTargetConfigurations []string `json:",omitempty"`
+	WhitelistTargets              []string `json:",omitempty"`
+	BlacklistTargets              []string `json:",omitempty"`
+
+	raw *orderedmap.OrderedMap
+}
+
+func (p *PluginReference) rawObject() *orderedmap.OrderedMap {
+	return p.raw
+}
+
+func (p *PluginReference) setRawObject(raw *orderedmap.OrderedMap) {
+	p.raw = raw
+}
+
+// moduleHolders 和 pluginHolders 将类型化的列表转换为 rawHolder 列表。
+func moduleHolders(modules []*ModuleDescriptor) []rawHolder {
+	holders := make([]rawHolder, len(modules))
+	for i, m := range modules {
+		if m != nil {
+			holders[i] = m
+		}
+	}
+
+	return holders
+}
+
+func pluginHolders(plugins []*PluginReference) []rawHolder {
+	holders := make([]rawHolder, len(plugins))
+	for i, p := range plugins {
+		if p != nil {
+			holders[i] = p
+		}
+	}
+
+	return holders
+}
+
+// findModule 在 module 列表中查找指定名字的 module。
+func findModule(modules []*ModuleDescriptor, name string) *ModuleDescriptor {
+	for _, m := range modules {
+		if m != nil && m.Name == name {
+			return m
+		}
+	}
+
+	return nil
+}
+
+// findPlugin 在插件引用列表中查找指定名字的插件。
+func findPlugin(plugins []*PluginReference, name string) *PluginReference {
+	for _, p := range plugins {
+		if p != nil && p.Name == name {
+			return p
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+package descriptor
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/iancoleman/orderedmap"
+)
+
+// PluginDescriptor 是 .uplugin 文件的模型，LocalizationTargets 等没有列出的字段会原样保留，参考 UE 的实现：
+// FPluginDescriptor::Read / FPluginDescriptor::Write
+type PluginDescriptor struct {
+	FileVersion              int
+	Version                  int
+	VersionName              string
+	FriendlyName             string
+	Description              string
+	Category                 string
+	CreatedBy                string
+	CreatedByURL             string
+	DocsURL                  string
+	MarketplaceURL           string
+	SupportURL               string
+	EngineVersion            string   `json:",omitempty"`
+	EditorCustomVersion      int      `json:",omitempty"`
+	EnabledByDefault         *bool    `json:",omitempty"` // 没有指定时为 nil，工程中的插件默认启用，引擎中的插件默认不启用
+	CanContainContent        bool     `json:",omitempty"`
+	CanContainVerse          bool     `json:",omitempty"`
+	IsBetaVersion            bool     `json:",omitempty"`
+	IsExperimentalVersion    bool     `json:",omitempty"`
+	Installed                bool     `json:",omitempty"`
+	SupportedTargetPlatforms []string `json:",omitempty"`
+	SupportedPrograms        []string `json:",omitempty"`
+	HasExplicitPlatforms     bool     `json:",omitempty"`
+	RequiresBuildPlatform    bool     `json:",omitempty"`
+	ExplicitlyLoaded         bool     `json:",omitempty"`
+	IsSealed                 bool     `json:",omitempty"`
+	NoCode                   bool     `json:",omitempty"`
+	IsHidden                 bool     `json:",omitempty"`
+	Modules                  []*ModuleDescriptor
+	Plugins                  []*PluginReference
+
+	raw    *orderedmap.OrderedMap
+	format format
+}
+
+// ParsePlugin 解析 .uplugin 文件的内容。
+func ParsePlugin(content []byte) (*PluginDescriptor, error) {
+	var d PluginDescriptor
+	raw, f, err := parse(content, &d)
+	if err != nil {
+		return nil, err
+	}
+
+	d.raw = raw
+	d.format = f
+	attachRawObjects(raw, "Modules", moduleHolders(d.Modules))
+	attachRawObjects(raw, "Plugins", pluginHolders(d.Plugins))
+	return &d, nil
+}
+
+// LoadPlugin 读取并解析 .uplugin 文件。
+func LoadPlugin(path string) (*PluginDescriptor, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("open plugin file: %w", err)
+	}
+
+	d, err := ParsePlugin(content)
+	if err != nil {
+		return nil, fmt.Errorf("parse plugin file %s: %w", path, err)
+	}
+
+	return d, nil
+}
+
+// Marshal 输出修改后的 .uplugin 文件内容，保持原有的格式。
+func (d *PluginDescriptor) Marshal() ([]byte, error) {
+	isNew := d.raw == nil
+	if isNew {
+		d.raw = newObject()
+		d.format = format{indent: "\t", trailingNewline: true}
+	}
+
+	content, err := marshal(d.raw, d, d.format, isNew)
+	if err != nil {
+		return nil, err
+	}
+
+	// 之后再次修改时基于这次输出的内容
+	d.format.content = content
+	return content, nil
+}
+
+// Save 将修改后的内容写入 .uplugin 文件。
+func (d *PluginDescriptor) Save(path string) error {
+	content, err := d.Marshal()
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		return fmt.Errorf("write plugin file: %w", err)
+	}
+
+	return nil
+}
+
+// FindModule 查找指定名字的 module，找不到时返回 nil。
+func (d *PluginDescriptor) FindModule(name string) *ModuleDescriptor {
+	return findModule(d.Modules, name)
+}
+
+// FindPlugin 查找指定名字的插件引用，找不到时返回 nil。
+func (d *PluginDescriptor) FindPlugin(name string) *PluginReference {
+	return findPlugin(d.Plugins, name)
+}
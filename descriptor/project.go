@@ -0,0 +1,100 @@
+package descriptor
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/iancoleman/orderedmap"
+)
+
+// ProjectDescriptor 是 .uproject 文件的模型，PreBuildSteps 等没有列出的字段会原样保留，参考 UE 的实现：
+// FProjectDescriptor::Read / FProjectDescriptor::Write
+type ProjectDescriptor struct {
+	FileVersion                   int
+	EngineAssociation             string
+	Category                      string
+	Description                   string
+	IsEnterpriseProject           bool `json:"Enterprise,omitempty"`
+	DisableEnginePluginsByDefault bool `json:",omitempty"`
+	Modules                       []*ModuleDescriptor
+	Plugins                       []*PluginReference
+	AdditionalRootDirectories     []string
+	AdditionalPluginDirectories   []string
+	TargetPlatforms               []string
+	EpicSampleNameHash            string
+
+	raw    *orderedmap.OrderedMap
+	format format
+}
+
+// ParseProject 解析 .uproject 文件的内容。
+func ParseProject(content []byte) (*ProjectDescriptor, error) {
+	var d ProjectDescriptor
+	raw, f, err := parse(content, &d)
+	if err != nil {
+		return nil, err
+	}
+
+	d.raw = raw
+	d.format = f
+	attachRawObjects(raw, "Modules", moduleHolders(d.Modules))
+	attachRawObjects(raw, "Plugins", pluginHolders(d.Plugins))
+	return &d, nil
+}
+
+// LoadProject 读取并解析 .uproject 文件。
+func LoadProject(path string) (*ProjectDescriptor, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("open project file: %w", err)
+	}
+
+	d, err := ParseProject(content)
+	if err != nil {
+		return nil, fmt.Errorf("parse project file %s: %w", path, err)
+	}
+
+	return d, nil
+}
+
+// Marshal 输出修改后的 .uproject 文件内容，保持原有的格式。
+func (d *ProjectDescriptor) Marshal() ([]byte, error) {
+	isNew := d.raw == nil
+	if isNew {
+		d.raw = newObject()
+		d.format = format{indent: "\t", trailingNewline: true}
+	}
+
+	content, err := marshal(d.raw, d, d.format, isNew)
+	if err != nil {
+		return nil, err
+	}
+
+	// 之后再次修改时基于这次输出的内容
+	d.format.content = content
+	return content, nil
+}
+
+// Save 将修改后的内容写入 .uproject 文件。
+func (d *ProjectDescriptor) Save(path string) error {
+	content, err := d.Marshal()
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		return fmt.Errorf("write project file: %w", err)
+	}
+
+	return nil
+}
+
+// FindModule 查找指定名字的 module，找不到时返回 nil。
+func (d *ProjectDescriptor) FindModule(name string) *ModuleDescriptor {
+	return findModule(d.Modules, name)
+}
+
+// FindPlugin 查找指定名字的插件引用，找不到时返回 nil。
+func (d *ProjectDescriptor) FindPlugin(name string) *PluginReference {
+	return findPlugin(d.Plugins, name)
+}
@@ -0,0 +1,446 @@
+package descriptor
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/iancoleman/orderedmap"
+)
+
+// jsonNode 是 JSON 值在原始内容中的范围，对象和数组还记录了每个成员的范围。
+type jsonNode struct {
+	start     int
+	end       int
+	keys      []string    // 对象中的 key
+	keyStarts []int       // 对象中每个 key 的起始位置
+	keyEnds   []int       // 对象中每个 key 的结束位置，用于获取 key 和值之间的分隔符
+	children  []*jsonNode // 对象中每个 key 对应的值，或者数组中的元素
+}
+
+// jsonScanner 扫描 JSON 内容得到每个值的范围，内容已经被 json.Unmarshal 检查过，所以只做简单的校验。
+type jsonScanner struct {
+	content []byte
+	pos     int
+}
+
+func (s *jsonScanner) peek() byte {
+	if s.pos < len(s.content) {
+		return s.content[s.pos]
+	}
+
+	return 0
+}
+
+func (s *jsonScanner) skipSpace() {
+	for s.pos < len(s.content) && bytes.IndexByte([]byte(" \t\r\n"), s.content[s.pos]) >= 0 {
+		s.pos++
+	}
+}
+
+func (s *jsonScanner) scanString() error {
+	if s.peek() != '"' {
+		return fmt.Errorf("expect string at offset %d", s.pos)
+	}
+
+	for s.pos++; s.pos < len(s.content); s.pos++ {
+		switch s.content[s.pos] {
+		case '\\':
+			s.pos++
+		case '"':
+			s.pos++
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unterminated string")
+}
+
+// scanMembers 扫描对象或者数组的成员，直到遇到 end。
+func (s *jsonScanner) scanMembers(n *jsonNode, end byte, isObject bool) error {
+	s.pos++
+	s.skipSpace()
+	if s.peek() == end {
+		s.pos++
+		return nil
+	}
+
+	for {
+		if isObject {
+			s.skipSpace()
+			keyStart := s.pos
+			if err := s.scanString(); err != nil {
+				return err
+			}
+
+			var key string
+			if err := json.Unmarshal(s.content[keyStart:s.pos], &key); err != nil {
+				return err
+			}
+
+			n.keys = append(n.keys, key)
+			n.keyStarts = append(n.keyStarts, keyStart)
+			n.keyEnds = append(n.keyEnds, s.pos)
+			s.skipSpace()
+			if s.peek() != ':' {
+				return fmt.Errorf("expect ':' at offset %d", s.pos)
+			}
+			s.pos++
+		}
+
+		child, err := s.scanValue()
+		if err != nil {
+			return err
+		}
+
+		n.children = append(n.children, child)
+		s.skipSpace()
+		switch s.peek() {
+		case ',':
+			s.pos++
+		case end:
+			s.pos++
+			return nil
+		default:
+			return fmt.Errorf("expect ',' or '%c' at offset %d", end, s.pos)
+		}
+	}
+}
+
+func (s *jsonScanner) scanValue() (*jsonNode, error) {
+	s.skipSpace()
+	n := &jsonNode{start: s.pos}
+	var err error
+	switch s.peek() {
+	case 0:
+		return nil, fmt.Errorf("unexpected end of JSON")
+	case '{':
+		err = s.scanMembers(n, '}', true)
+	case '[':
+		err = s.scanMembers(n, ']', false)
+	case '"':
+		err = s.scanString()
+	default:
+		for s.pos < len(s.content) && bytes.IndexByte([]byte(",}] \t\r\n"), s.content[s.pos]) < 0 {
+			s.pos++
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	n.end = s.pos
+	return n, nil
+}
+
+// derefObject 将 *orderedmap.OrderedMap 转换为 orderedmap.OrderedMap，以便和解析得到的值比较。
+func derefObject(v interface{}) interface{} {
+	if p, ok := v.(*orderedmap.OrderedMap); ok && p != nil {
+		return *p
+	}
+
+	return v
+}
+
+// equalValue 比较两个 JSON 值是否相同，对象的 key 的顺序也需要相同。
+func equalValue(a interface{}, b interface{}) bool {
+	a, b = derefObject(a), derefObject(b)
+	switch av := a.(type) {
+	case orderedmap.OrderedMap:
+		bv, ok := b.(orderedmap.OrderedMap)
+		if !ok || !reflect.DeepEqual(av.Keys(), bv.Keys()) {
+			return false
+		}
+
+		for _, key := range av.Keys() {
+			x, _ := av.Get(key)
+			y, _ := bv.Get(key)
+			if !equalValue(x, y) {
+				return false
+			}
+		}
+
+		return true
+	case []interface{}:
+		bv, ok := b.([]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+
+		for i := range av {
+			if !equalValue(av[i], bv[i]) {
+				return false
+			}
+		}
+
+		return true
+	default:
+		return reflect.DeepEqual(a, b)
+	}
+}
+
+// lineIndent 获取 pos 所在行的缩进。
+func lineIndent(content []byte, pos int) string {
+	start := bytes.LastIndexByte(content[:pos], '\n') + 1
+	end := start
+	for end < pos && (content[end] == ' ' || content[end] == '\t') {
+		end++
+	}
+
+	return string(content[start:end])
+}
+
+// splicer 根据修改后的值生成描述文件的内容，没有修改的部分直接使用原有的内容，
+// 以保留原有的空白、紧凑的对象和 1.0 这样的数字写法。
+type splicer struct {
+	content []byte
+	format  format
+}
+
+// render 生成新的值，multiline 时按照 prefix 和文件的缩进生成多行的内容，否则生成紧凑的内容。
+func (sp *splicer) render(v interface{}, prefix string, multiline bool) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+
+	compact := bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+	if !multiline {
+		return compact, nil
+	}
+
+	var out bytes.Buffer
+	if err := json.Indent(&out, compact, prefix, sp.format.indent); err != nil {
+		return nil, err
+	}
+
+	if sp.format.crlf {
+		return bytes.ReplaceAll(out.Bytes(), []byte("\n"), []byte("\r\n")), nil
+	}
+
+	return out.Bytes(), nil
+}
+
+// value 生成节点 n 修改后的内容，old 是原有的值，newValue 是修改后的值。
+func (sp *splicer) value(n *jsonNode, old interface{}, newValue interface{}) ([]byte, error) {
+	orig := sp.content[n.start:n.end]
+	if equalValue(old, newValue) {
+		return orig, nil
+	}
+
+	switch nv := derefObject(newValue).(type) {
+	case orderedmap.OrderedMap:
+		if ov, ok := derefObject(old).(orderedmap.OrderedMap); ok && len(n.keys) != 0 {
+			if out, ok, err := sp.object(n, ov, nv); err != nil || ok {
+				return out, err
+			}
+		}
+	case []interface{}:
+		if ov, ok := old.([]interface{}); ok && len(n.children) != 0 && len(ov) == len(n.children) {
+			if out, ok, err := sp.array(n, ov, nv); err != nil || ok {
+				return out, err
+			}
+		}
+	}
+
+	// 无法只修改部分内容时重新生成整个值，原来是单行的非空对象或数组时保持单行
+	multiline := bytes.ContainsRune(orig, '\n') || len(n.children) == 0
+	return sp.render(newValue, lineIndent(sp.content, n.start), multiline)
+}
+
+// memberStyle 获取对象或数组中第一个成员之前的内容，新的成员使用同样的格式。
+// 返回成员前的空白、新成员的值是否需要多行以及多行时的缩进。
+func (sp *splicer) memberStyle(n *jsonNode, firstStart int) ([]byte, bool, string) {
+	lead := sp.content[n.start+1 : firstStart]
+	if i := bytes.LastIndexByte(lead, '\n'); i >= 0 {
+		return lead, true, string(lead[i+1:])
+	}
+
+	return lead, false, ""
+}
+
+// object 在对象中修改和插入成员，原有的 key 都还在并且顺序不变时返回 true，否则需要重新生成整个对象。
+func (sp *splicer) object(n *jsonNode, old orderedmap.OrderedMap, newValue orderedmap.OrderedMap) ([]byte, bool, error) {
+	oldIndex := map[string]int{}
+	for i, key := range n.keys {
+		oldIndex[key] = i
+	}
+
+	next := 0
+	for _, key := range newValue.Keys() {
+		if i, ok := oldIndex[key]; ok {
+			if i != next {
+				return nil, false, nil
+			}
+			next++
+		}
+	}
+
+	if next != len(n.keys) {
+		return nil, false, nil
+	}
+
+	lead, multiline, indent := sp.memberStyle(n, n.keyStarts[0])
+	sep := sp.content[n.keyEnds[0]:n.children[0].start]
+	member := func(key string) ([]byte, error) {
+		value, _ := newValue.Get(key)
+		rendered, err := sp.render(value, indent, multiline)
+		if err != nil {
+			return nil, err
+		}
+
+		return append(append(mustMarshalString(key), sep...), rendered...), nil
+	}
+
+	var out bytes.Buffer
+	cursor := n.start + 1
+	out.Write(sp.content[n.start:cursor])
+	var front [][]byte
+	for _, key := range newValue.Keys() {
+		i, ok := oldIndex[key]
+		if !ok {
+			m, err := member(key)
+			if err != nil {
+				return nil, false, err
+			}
+
+			if cursor == n.start+1 {
+				// 插入到第一个原有的成员之前
+				front = append(front, m)
+			} else {
+				out.WriteString(",")
+				out.Write(lead)
+				out.Write(m)
+			}
+			continue
+		}
+
+		for _, m := range front {
+			out.Write(lead)
+			out.Write(m)
+			out.WriteString(",")
+		}
+		front = nil
+
+		oldValue, _ := old.Get(key)
+		value, _ := newValue.Get(key)
+		patched, err := sp.value(n.children[i], oldValue, value)
+		if err != nil {
+			return nil, false, err
+		}
+
+		out.Write(sp.content[cursor:n.children[i].start])
+		out.Write(patched)
+		cursor = n.children[i].end
+	}
+
+	out.Write(sp.content[cursor:n.end])
+	return out.Bytes(), true, nil
+}
+
+// array 修改数组中的元素，元素个数不变时逐个修改，只插入了新元素时只写入新元素，否则需要重新生成整个数组。
+func (sp *splicer) array(n *jsonNode, old []interface{}, newValue []interface{}) ([]byte, bool, error) {
+	matched := make([]int, len(newValue))
+	if len(newValue) == len(old) {
+		for i := range matched {
+			matched[i] = i
+		}
+	} else {
+		j := 0
+		for i, v := range newValue {
+			matched[i] = -1
+			if j < len(old) && equalValue(old[j], v) {
+				matched[i] = j
+				j++
+			}
+		}
+
+		if j != len(old) {
+			return nil, false, nil
+		}
+	}
+
+	lead, multiline, indent := sp.memberStyle(n, n.children[0].start)
+	var out bytes.Buffer
+	cursor := n.start + 1
+	out.Write(sp.content[n.start:cursor])
+	var front [][]byte
+	for i, v := range newValue {
+		j := matched[i]
+		if j < 0 {
+			rendered, err := sp.render(v, indent, multiline)
+			if err != nil {
+				return nil, false, err
+			}
+
+			if cursor == n.start+1 {
+				front = append(front, rendered)
+			} else {
+				out.WriteString(",")
+				out.Write(lead)
+				out.Write(rendered)
+			}
+			continue
+		}
+
+		for _, m := range front {
+			out.Write(lead)
+			out.Write(m)
+			out.WriteString(",")
+		}
+		front = nil
+
+		patched, err := sp.value(n.children[j], old[j], v)
+		if err != nil {
+			return nil, false, err
+		}
+
+		out.Write(sp.content[cursor:n.children[j].start])
+		out.Write(patched)
+		cursor = n.children[j].end
+	}
+
+	out.Write(sp.content[cursor:n.end])
+	return out.Bytes(), true, nil
+}
+
+func mustMarshalString(s string) []byte {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s)
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+}
+
+// splice 将修改后的 raw 写回原有的内容，只替换和插入修改过的值。
+func splice(content []byte, f format, raw *orderedmap.OrderedMap) ([]byte, error) {
+	s := &jsonScanner{content: content}
+	if f.bom {
+		s.pos = len(utf8Bom)
+	}
+
+	root, err := s.scanValue()
+	if err != nil {
+		return nil, fmt.Errorf("scan descriptor: %w", err)
+	}
+
+	var old orderedmap.OrderedMap
+	if err := json.Unmarshal(content[root.start:root.end], &old); err != nil {
+		return nil, fmt.Errorf("unmarshal descriptor: %w", err)
+	}
+
+	sp := &splicer{content: content, format: f}
+	body, err := sp.value(root, old, raw)
+	if err != nil {
+		return nil, fmt.Errorf("marshal descriptor: %w", err)
+	}
+
+	out := make([]byte, 0, len(content)+len(body)-(root.end-root.start))
+	out = append(out, content[:root.start]...)
+	out = append(out, body...)
+	return append(out, content[root.end:]...), nil
+}
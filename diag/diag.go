@@ -0,0 +1,167 @@
+package diag
+
+import (
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Severity 是诊断信息的严重程度。
+type Severity string
+
+const (
+	SeverityError   = Severity("error")   // 错误
+	SeverityWarning = Severity("warning") // 警告
+	SeverityNote    = Severity("note")    // 附加说明
+)
+
+// Diagnostic 是从 UBT 或编译器输出中解析出来的一条诊断信息。
+type Diagnostic struct {
+	File     string   `json:"file,omitempty"`
+	Line     int      `json:"line,omitempty"`
+	Column   int      `json:"column,omitempty"`
+	Severity Severity `json:"severity"`
+	Code     string   `json:"code,omitempty"`
+	Message  string   `json:"message"`
+	Module   string   `json:"module,omitempty"`
+	Tool     string   `json:"tool"`
+}
+
+var (
+	// clang: /path/File.cpp:12:5: error: message [-Wflag]
+	clangRegexp = regexp.MustCompile(`^(.+?):(\d+):(?:(\d+):)?\s*(fatal error|error|warning|note):\s*(.*)$`)
+	// MSVC: C:\Path\File.cpp(12): error C2065: message
+	msvcRegexp = regexp.MustCompile(`^(.+?)\((\d+)(?:,(\d+))?\)\s*:\s*(fatal error|error|warning|note)\s*([A-Z]+\d+)?\s*:\s*(.*)$`)
+	// UHT: /path/File.h(45): Error: message 或者 Error: /path/File.h(45) : message
+	uhtFileFirstRegexp  = regexp.MustCompile(`^(?:LogCompile:\s*)?(.+?)\((\d+)\)\s*:\s*(Error|Warning):\s*(.*)$`)
+	uhtLevelFirstRegexp = regexp.MustCompile(`^(?:LogCompile:\s*)?(Error|Warning):\s*(?:(.+?)\((\d+)\)\s*:\s*)?(.*)$`)
+	// UBT: ERROR: message
+	ubtRegexp = regexp.MustCompile(`^(ERROR|WARNING):\s*(.*)$`)
+	// 没有文件信息的工具输出，如 LINK : fatal error LNK1181: message
+	toolRegexp = regexp.MustCompile(`^(\S[^:]*?)\s+:\s*(fatal error|error|warning)\s*([A-Z]+\d+)?\s*:\s*(.*)$`)
+	// clang 的 warning 会在消息末尾带上对应的开关，如 [-Wunused-variable]
+	clangFlagRegexp = regexp.MustCompile(`\s*\[(-W[^\]]+)\]$`)
+)
+
+func toSeverity(s string) Severity {
+	switch strings.ToLower(s) {
+	case "fatal error", "error":
+		return SeverityError
+	case "warning":
+		return SeverityWarning
+	default:
+		return SeverityNote
+	}
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+// engineSourceGroups 是引擎 Source 目录下用于给 module 分组的目录名。
+var engineSourceGroups = []string{"Runtime", "Editor", "Developer", "Programs", "ThirdParty"}
+
+// ModuleOfFile 根据文件路径推断文件所属的 module，推断不出来时返回空字符串。
+// 如 Source/MyModule/Private/A.cpp 属于 MyModule，
+// UHT 生成的 Intermediate/Build/Win64/UnrealEditor/Inc/MyModule/UHT/A.gen.cpp 也属于 MyModule。
+func ModuleOfFile(file string) string {
+	parts := strings.FieldsFunc(filepath.ToSlash(file), func(r rune) bool { return r == '/' || r == '\\' })
+	for i := len(parts) - 2; i >= 0; i-- {
+		switch parts[i] {
+		case "Inc":
+			return parts[i+1]
+		case "Source":
+			module := parts[i+1]
+			for _, group := range engineSourceGroups {
+				if module == group && i+2 < len(parts)-1 {
+					return parts[i+2]
+				}
+			}
+
+			if i+1 < len(parts)-1 {
+				return module
+			}
+		}
+	}
+
+	return ""
+}
+
+// ParseLine 解析一行输出，不是诊断信息时返回 nil。
+func ParseLine(line string) *Diagnostic {
+	line = strings.TrimSpace(line)
+	if len(line) == 0 {
+		return nil
+	}
+
+	var d *Diagnostic
+	if m := msvcRegexp.FindStringSubmatch(line); m != nil {
+		d = &Diagnostic{File: m[1], Line: atoi(m[2]), Column: atoi(m[3]),
+			Severity: toSeverity(m[4]), Code: m[5], Message: m[6], Tool: "msvc"}
+	} else if m := uhtFileFirstRegexp.FindStringSubmatch(line); m != nil {
+		d = &Diagnostic{File: m[1], Line: atoi(m[2]), Severity: toSeverity(m[3]), Message: m[4], Tool: "uht"}
+	} else if m := clangRegexp.FindStringSubmatch(line); m != nil {
+		d = &Diagnostic{File: m[1], Line: atoi(m[2]), Column: atoi(m[3]),
+			Severity: toSeverity(m[4]), Message: m[5], Tool: "clang"}
+		if flag := clangFlagRegexp.FindStringSubmatch(d.Message); flag != nil {
+			d.Code = flag[1]
+			d.Message = strings.TrimSuffix(d.Message, flag[0])
+		}
+	} else if m := ubtRegexp.FindStringSubmatch(line); m != nil {
+		d = &Diagnostic{Severity: toSeverity(m[1]), Message: m[2], Tool: "ubt"}
+	} else if m := uhtLevelFirstRegexp.FindStringSubmatch(line); m != nil {
+		d = &Diagnostic{File: m[2], Line: atoi(m[3]), Severity: toSeverity(m[1]), Message: m[4], Tool: "uht"}
+	} else if m := toolRegexp.FindStringSubmatch(line); m != nil {
+		d = &Diagnostic{Severity: toSeverity(m[2]), Code: m[3], Message: m[4], Tool: m[1]}
+	} else {
+		return nil
+	}
+
+	d.Message = strings.TrimSpace(d.Message)
+	if len(d.File) != 0 {
+		d.Module = ModuleOfFile(d.File)
+	}
+
+	return d
+}
+
+// Parser 逐行解析 UBT 和编译器的输出，收集其中的诊断信息。
+type Parser struct {
+	diagnostics []*Diagnostic
+	seen        map[Diagnostic]bool
+}
+
+// NewParser 创建一个新的 Parser。
+func NewParser() *Parser {
+	return &Parser{seen: make(map[Diagnostic]bool)}
+}
+
+// Feed 解析一行输出，重复的诊断信息只会保留一条。
+func (p *Parser) Feed(line string) {
+	d := ParseLine(line)
+	if d == nil || p.seen[*d] {
+		return
+	}
+
+	p.seen[*d] = true
+	p.diagnostics = append(p.diagnostics, d)
+}
+
+// Diagnostics 返回目前收集到的所有诊断信息。
+func (p *Parser) Diagnostics() []*Diagnostic {
+	return p.diagnostics
+}
+
+// Count 统计给定严重程度的诊断信息数量。
+func Count(diagnostics []*Diagnostic, severity Severity) int {
+	n := 0
+	for _, d := range diagnostics {
+		if d.Severity == severity {
+			n++
+		}
+	}
+
+	return n
+}
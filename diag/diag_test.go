@@ -0,0 +1,104 @@
+package diag
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestParseLine 测试 ParseLine 函数。
+func TestParseLine(t *testing.T) {
+	cases := []struct {
+		name   string
+		line   string
+		expect *Diagnostic
+	}{
+		{
+			name: "msvc error",
+			line: `D:\Game\Source\MyGame\Private\MyActor.cpp(42): error C2065: 'Foo': undeclared identifier`,
+			expect: &Diagnostic{File: `D:\Game\Source\MyGame\Private\MyActor.cpp`, Line: 42, Severity: SeverityError,
+				Code: "C2065", Message: "'Foo': undeclared identifier", Module: "MyGame", Tool: "msvc"},
+		},
+		{
+			name: "msvc warning with column",
+			line: `D:\Game\Plugins\Foo\Source\FooCore\Public\Foo.h(7,13): warning C4996: 'Bar': deprecated`,
+			expect: &Diagnostic{File: `D:\Game\Plugins\Foo\Source\FooCore\Public\Foo.h`, Line: 7, Column: 13,
+				Severity: SeverityWarning, Code: "C4996", Message: "'Bar': deprecated", Module: "FooCore", Tool: "msvc"},
+		},
+		{
+			name: "clang error",
+			line: `/home/me/Game/Source/MyGame/Private/MyActor.cpp:42:7: error: use of undeclared identifier 'Foo'`,
+			expect: &Diagnostic{File: "/home/me/Game/Source/MyGame/Private/MyActor.cpp", Line: 42, Column: 7,
+				Severity: SeverityError, Message: "use of undeclared identifier 'Foo'", Module: "MyGame", Tool: "clang"},
+		},
+		{
+			name: "clang warning with flag",
+			line: `/opt/UE/Engine/Source/Runtime/Core/Public/Misc/A.h:3:1: warning: unused variable 'x' [-Wunused-variable]`,
+			expect: &Diagnostic{File: "/opt/UE/Engine/Source/Runtime/Core/Public/Misc/A.h", Line: 3, Column: 1,
+				Severity: SeverityWarning, Code: "-Wunused-variable", Message: "unused variable 'x'", Module: "Core", Tool: "clang"},
+		},
+		{
+			name: "uht file first",
+			line: `D:\Game\Source\MyGame\Public\MyActor.h(12): Error: Unrecognized type 'FBar'`,
+			expect: &Diagnostic{File: `D:\Game\Source\MyGame\Public\MyActor.h`, Line: 12, Severity: SeverityError,
+				Message: "Unrecognized type 'FBar'", Module: "MyGame", Tool: "uht"},
+		},
+		{
+			name:   "uht level first",
+			line:   `LogCompile: Error: Missing '*' in Expected a pointer type`,
+			expect: &Diagnostic{Severity: SeverityError, Message: "Missing '*' in Expected a pointer type", Tool: "uht"},
+		},
+		{
+			name:   "ubt error",
+			line:   `ERROR: Could not find definition for module 'Foo', (referenced via Target -> MyGame.Build.cs)`,
+			expect: &Diagnostic{Severity: SeverityError, Message: "Could not find definition for module 'Foo', (referenced via Target -> MyGame.Build.cs)", Tool: "ubt"},
+		},
+		{
+			name:   "linker error",
+			line:   `LINK : fatal error LNK1181: cannot open input file 'Foo.lib'`,
+			expect: &Diagnostic{Severity: SeverityError, Code: "LNK1181", Message: "cannot open input file 'Foo.lib'", Tool: "LINK"},
+		},
+		{
+			name:   "generated file",
+			line:   `/g/Intermediate/Build/Linux/UnrealEditor/Inc/MyGame/UHT/MyActor.gen.cpp:10:2: note: here`,
+			expect: &Diagnostic{File: "/g/Intermediate/Build/Linux/UnrealEditor/Inc/MyGame/UHT/MyActor.gen.cpp", Line: 10, Column: 2, Severity: SeverityNote, Message: "here", Module: "MyGame", Tool: "clang"},
+		},
+		{
+			name:   "normal output",
+			line:   `Building 3 actions with 8 processes...`,
+			expect: nil,
+		},
+	}
+
+	for i, c := range cases {
+		actual := ParseLine(c.line)
+		if (actual == nil) != (c.expect == nil) || (actual != nil && *actual != *c.expect) {
+			t.Errorf("%d:%s:\nexpect:\n%+v\n\nactual:\n%+v", i, c.name, c.expect, actual)
+		}
+	}
+}
+
+// TestWriteQuickfix 测试 quickfix 格式的导出和重复信息的去重。
+func TestWriteQuickfix(t *testing.T) {
+	parser := NewParser()
+	for _, line := range []string{
+		`/g/Source/MyGame/A.cpp:1:2: error: bad`,
+		`/g/Source/MyGame/A.cpp:1:2: error: bad`,
+		`D:\g\Source\MyGame\B.cpp(3): warning C4100: unused`,
+		`ERROR: UBT failed`,
+	} {
+		parser.Feed(line)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteQuickfix(&buf, parser.Diagnostics()); err != nil {
+		t.Fatal(err)
+	}
+
+	expect := `/g/Source/MyGame/A.cpp:1:2: error: bad
+D:\g\Source\MyGame\B.cpp:3:1: warning: unused [C4100]
+ubt: error: UBT failed
+`
+	if buf.String() != expect {
+		t.Errorf("expect:\n%s\n\nactual:\n%s", expect, buf.String())
+	}
+}
@@ -0,0 +1,130 @@
+package diag
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+)
+
+// 支持的导出格式。
+const (
+	FormatJSON     = "json"
+	FormatQuickfix = "quickfix"
+	FormatSummary  = "summary"
+)
+
+// AvailableFormats 是所有支持的导出格式。
+var AvailableFormats = []string{FormatJSON, FormatQuickfix, FormatSummary}
+
+// Write 按指定格式导出诊断信息。
+func Write(w io.Writer, format string, diagnostics []*Diagnostic) error {
+	switch format {
+	case FormatJSON:
+		return WriteJSON(w, diagnostics)
+	case FormatQuickfix:
+		return WriteQuickfix(w, diagnostics)
+	case FormatSummary:
+		return WriteSummary(w, diagnostics)
+	default:
+		return fmt.Errorf("unknown diagnostics format %s", format)
+	}
+}
+
+// WriteJSON 以 JSON 数组的格式导出诊断信息。
+func WriteJSON(w io.Writer, diagnostics []*Diagnostic) error {
+	if diagnostics == nil {
+		diagnostics = []*Diagnostic{}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(diagnostics)
+}
+
+// FormatQuickfixLine 将诊断信息格式化为 file:line:col: severity: message [code] 的形式，
+// 可以直接被 vim 的 quickfix 和 VS Code 的 $gcc problem matcher 识别。
+func FormatQuickfixLine(d *Diagnostic) string {
+	msg := fmt.Sprintf("%s: %s", d.Severity, d.Message)
+	if len(d.Code) != 0 {
+		msg = fmt.Sprintf("%s [%s]", msg, d.Code)
+	}
+
+	if len(d.File) == 0 {
+		return fmt.Sprintf("%s: %s", d.Tool, msg)
+	}
+
+	col := d.Column
+	if col == 0 {
+		col = 1
+	}
+
+	return fmt.Sprintf("%s:%d:%d: %s", d.File, d.Line, col, msg)
+}
+
+// WriteQuickfix 以 quickfix 格式导出诊断信息，每行一条。
+func WriteQuickfix(w io.Writer, diagnostics []*Diagnostic) error {
+	for _, d := range diagnostics {
+		if _, err := fmt.Fprintln(w, FormatQuickfixLine(d)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type moduleSummary struct {
+	module   string
+	errors   int
+	warnings int
+	notes    int
+}
+
+// WriteSummary 按 module 统计诊断信息数量并以表格的形式输出。
+func WriteSummary(w io.Writer, diagnostics []*Diagnostic) error {
+	summaries := make(map[string]*moduleSummary)
+	for _, d := range diagnostics {
+		module := d.Module
+		if len(module) == 0 {
+			module = "-"
+		}
+
+		s, ok := summaries[module]
+		if !ok {
+			s = &moduleSummary{module: module}
+			summaries[module] = s
+		}
+
+		switch d.Severity {
+		case SeverityError:
+			s.errors++
+		case SeverityWarning:
+			s.warnings++
+		default:
+			s.notes++
+		}
+	}
+
+	var sorted []*moduleSummary
+	for _, s := range summaries {
+		sorted = append(sorted, s)
+	}
+
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].errors != sorted[j].errors {
+			return sorted[i].errors > sorted[j].errors
+		}
+		return sorted[i].module < sorted[j].module
+	})
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "MODULE\tERRORS\tWARNINGS\tNOTES")
+	for _, s := range sorted {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\n", s.module, s.errors, s.warnings, s.notes)
+	}
+	fmt.Fprintf(tw, "TOTAL\t%d\t%d\t%d\n", Count(diagnostics, SeverityError),
+		Count(diagnostics, SeverityWarning), Count(diagnostics, SeverityNote))
+	return tw.Flush()
+}
@@ -0,0 +1,23 @@
+package enginecmd
+
+import "fmt"
+
+// Cmd 是 engine 子命令的集合。
+type Cmd struct {
+	RegisterCommand   *EngineRegisterCmd   `arg:"subcommand:register" help:"register an engine dir as a source build."`
+	UnregisterCommand *EngineUnregisterCmd `arg:"subcommand:unregister" help:"unregister a source build engine."`
+	ListCommand       *EngineListCmd       `arg:"subcommand:list" help:"list all known engines."`
+}
+
+// Run 实现了 subCmd 的接口。
+func (cmd *Cmd) Run() error {
+	if cmd.RegisterCommand != nil {
+		return cmd.RegisterCommand.Run()
+	} else if cmd.UnregisterCommand != nil {
+		return cmd.UnregisterCommand.Run()
+	} else if cmd.ListCommand != nil {
+		return cmd.ListCommand.Run()
+	}
+
+	return fmt.Errorf("missing target: register/unregister/list")
+}
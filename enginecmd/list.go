@@ -0,0 +1,35 @@
+package enginecmd
+
+import (
+	"fmt"
+
+	"github.com/zhiruili/urem/unreal"
+)
+
+// EngineListCmd 是用于列出所有已知引擎的子命令。
+type EngineListCmd struct{}
+
+// Run 执行列出引擎的操作。
+func (cmd *EngineListCmd) Run() error {
+	infos, err := unreal.FindAllEngineInfos()
+	if err != nil {
+		return fmt.Errorf("find Unreal engine info: %w", err)
+	}
+
+	for i, info := range infos {
+		if i != 0 {
+			fmt.Println("")
+		}
+
+		fmt.Printf("Identifier: %s\n", info.Version)
+		if ver := info.BuildVersion; ver != nil {
+			fmt.Printf("Build Version: %s (Branch: %s, Changelist: %d, CompatibleChangelist: %d)\n",
+				ver.String(), ver.BranchName, ver.Changelist, ver.CompatibleChangelist)
+		} else {
+			fmt.Printf("Build Version: unknown\n")
+		}
+		fmt.Printf("Install Path: %s\n", info.InstallPath)
+	}
+
+	return nil
+}
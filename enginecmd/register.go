@@ -0,0 +1,41 @@
+package enginecmd
+
+import (
+	"fmt"
+
+	"github.com/zhiruili/urem/core"
+	"github.com/zhiruili/urem/unreal"
+)
+
+// EngineRegisterCmd 是用于注册源码引擎的子命令。
+type EngineRegisterCmd struct {
+	Guid      string `arg:"-g,--guid" help:"GUID used to register the engine, generate a new one if not set"`
+	EngineDir string `arg:"positional,required" help:"root dir of the engine"`
+}
+
+// Run 执行注册操作。
+func (cmd *EngineRegisterCmd) Run() error {
+	guid, err := unreal.RegisterEngine(cmd.EngineDir, cmd.Guid)
+	if err != nil {
+		return fmt.Errorf("register engine: %w", err)
+	}
+
+	core.LogI("register engine %s as %s", cmd.EngineDir, guid)
+	return nil
+}
+
+// EngineUnregisterCmd 是用于取消注册源码引擎的子命令。
+type EngineUnregisterCmd struct {
+	Target string `arg:"positional,required" help:"GUID or root dir of the registered engine"`
+}
+
+// Run 执行取消注册操作。
+func (cmd *EngineUnregisterCmd) Run() error {
+	info, err := unreal.UnregisterEngine(cmd.Target)
+	if err != nil {
+		return fmt.Errorf("unregister engine: %w", err)
+	}
+
+	core.LogI("unregister engine %s at %s", info.Version, info.InstallPath)
+	return nil
+}
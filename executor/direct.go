@@ -0,0 +1,32 @@
+package executor
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+// Direct 是不经过 shell，直接执行程序的执行器。
+type Direct struct{}
+
+// NewDirect 创建一个直接执行程序的执行器。
+func NewDirect() *Direct {
+	return &Direct{}
+}
+
+// Execute 执行 args[0] 指定的程序，其余 args 作为程序的参数。
+func (d *Direct) Execute(args ...string) (stdOut string, stdErr string, err error) {
+	if len(args) == 0 {
+		return "", "", fmt.Errorf("missing program to execute")
+	}
+
+	return run(exec.Command(args[0], args[1:]...))
+}
+
+// Stream 执行 args[0] 指定的程序，并逐行处理输出。
+func (d *Direct) Stream(onLine LineHandler, args ...string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing program to execute")
+	}
+
+	return stream(exec.Command(args[0], args[1:]...), onLine)
+}
@@ -0,0 +1,146 @@
+package executor
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os/exec"
+	"runtime"
+	"sync"
+
+	"github.com/zhiruili/urem/encodeutil"
+)
+
+// LineHandler 用于处理子进程输出的每一行，isErr 表示该行是否来自标准错误输出。
+type LineHandler func(line string, isErr bool)
+
+// Executor 用于执行外部命令，不同的实现对 args 有不同的解释。
+type Executor interface {
+	// Execute 执行命令，返回标准输出和标准错误输出的内容。
+	Execute(args ...string) (stdOut string, stdErr string, err error)
+	// Stream 执行命令，并在子进程输出时逐行调用 onLine。
+	Stream(onLine LineHandler, args ...string) error
+}
+
+// Kind 表示执行器的类型。
+type Kind int
+
+const (
+	KindPowerShell = Kind(iota) // PowerShell，args 为 PowerShell 脚本
+	KindShell                   // POSIX shell，args 为 shell 脚本
+	KindDirect                  // 直接执行程序，args[0] 为程序路径，其余为程序参数
+)
+
+// String 实现了 fmt.Stringer interface。
+func (k Kind) String() string {
+	switch k {
+	case KindPowerShell:
+		return "PowerShell"
+	case KindShell:
+		return "Shell"
+	case KindDirect:
+		return "Direct"
+	default:
+		return fmt.Sprintf("Kind(%d)", int(k))
+	}
+}
+
+// override 不为空时，New 总是返回它，用于在测试中替换真实的执行器。
+var override Executor
+
+// Use 让之后所有 New 调用都返回 e，返回用于恢复的函数。
+func Use(e Executor) (restore func()) {
+	old := override
+	override = e
+	return func() {
+		override = old
+	}
+}
+
+// New 创建指定类型的执行器。
+func New(kind Kind) (Executor, error) {
+	if override != nil {
+		return override, nil
+	}
+
+	switch kind {
+	case KindPowerShell:
+		return NewPowerShell()
+	case KindShell:
+		return NewShell()
+	case KindDirect:
+		return NewDirect(), nil
+	default:
+		return nil, fmt.Errorf("unknown executor kind %s", kind)
+	}
+}
+
+// outputCharset 获取子进程输出使用的字符集，Windows 下使用系统默认的 GB18030。
+func outputCharset() encodeutil.Charset {
+	if runtime.GOOS == "windows" {
+		return encodeutil.GB18030
+	}
+
+	return encodeutil.UTF8
+}
+
+// maxLineSize 是逐行读取子进程输出时单行的最大长度。
+const maxLineSize = 1024 * 1024
+
+// scanLines 逐行读取 r 中的内容，去掉行尾的 \r 并转换字符集后交给 onLine。
+func scanLines(r io.Reader, onLine func(line string)) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+	for scanner.Scan() {
+		onLine(encodeutil.ByteToString(bytes.TrimRight(scanner.Bytes(), "\r"), outputCharset()))
+	}
+}
+
+// stream 执行命令，并在子进程输出时逐行调用 onLine，onLine 不会被并发调用。
+func stream(cmd *exec.Cmd, onLine LineHandler) error {
+	stdOut, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+
+	stdErr, err := cmd.StderrPipe()
+	if err != nil {
+		return err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	scan := func(r io.Reader, isErr bool) {
+		defer wg.Done()
+		scanLines(r, func(line string) {
+			mu.Lock()
+			onLine(line, isErr)
+			mu.Unlock()
+		})
+	}
+
+	wg.Add(2)
+	go scan(stdOut, false)
+	go scan(stdErr, true)
+	wg.Wait()
+
+	return cmd.Wait()
+}
+
+// run 执行命令并收集输出。
+func run(cmd *exec.Cmd) (stdOut string, stdErr string, err error) {
+	var outBuf bytes.Buffer
+	var errBuf bytes.Buffer
+	cmd.Stdout = &outBuf
+	cmd.Stderr = &errBuf
+
+	err = cmd.Run()
+	stdOut = encodeutil.ByteToString(outBuf.Bytes(), outputCharset())
+	stdErr = encodeutil.ByteToString(errBuf.Bytes(), outputCharset())
+	return
+}
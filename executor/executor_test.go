@@ -0,0 +1,169 @@
+package executor
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestScanLines 测试逐行读取子进程输出。
+func TestScanLines(t *testing.T) {
+	cases := []struct {
+		name    string
+		content string
+		expect  []string
+	}{
+		{
+			name:    "lf",
+			content: "a\nb\n",
+			expect:  []string{"a", "b"},
+		},
+		{
+			name:    "crlf",
+			content: "a\r\nb\r\n",
+			expect:  []string{"a", "b"},
+		},
+		{
+			name:    "no trailing newline",
+			content: "a\nb",
+			expect:  []string{"a", "b"},
+		},
+		{
+			name:    "empty lines",
+			content: "a\n\nb\n",
+			expect:  []string{"a", "", "b"},
+		},
+		{
+			name:    "empty",
+			content: "",
+			expect:  nil,
+		},
+		{
+			name:    "long line",
+			content: strings.Repeat("x", 100*1024) + "\n",
+			expect:  []string{strings.Repeat("x", 100*1024)},
+		},
+	}
+
+	for i, c := range cases {
+		var actual []string
+		scanLines(strings.NewReader(c.content), func(line string) {
+			actual = append(actual, line)
+		})
+
+		if !reflect.DeepEqual(actual, c.expect) {
+			t.Errorf("%d:%s: expect %q, actual %q", i, c.name, c.expect, actual)
+		}
+	}
+}
+
+// TestFakeExecute 测试 Fake 按顺序匹配预设结果并记录命令。
+func TestFakeExecute(t *testing.T) {
+	errBuild := errors.New("build failed")
+	fake := &Fake{
+		Responses: []*Response{
+			{Match: "Build.sh", StdOut: "built", Err: errBuild},
+			{Match: "reg query", StdOut: "first"},
+			{Match: "query", StdOut: "second"},
+		},
+	}
+
+	cases := []struct {
+		name      string
+		args      []string
+		expectOut string
+		expectErr error
+		expectOK  bool
+	}{
+		{name: "match joined args", args: []string{"/Engine/Build.sh", "Game"}, expectOut: "built", expectErr: errBuild, expectOK: true},
+		{name: "first match wins", args: []string{"reg", "query", "HKCU"}, expectOut: "first", expectOK: true},
+		{name: "later match", args: []string{"query"}, expectOut: "second", expectOK: true},
+		{name: "no match", args: []string{"ls"}, expectOK: false},
+	}
+
+	for i, c := range cases {
+		out, _, err := fake.Execute(c.args...)
+		if !c.expectOK {
+			if err == nil {
+				t.Errorf("%d:%s: expect error", i, c.name)
+			}
+			continue
+		}
+
+		if out != c.expectOut || err != c.expectErr {
+			t.Errorf("%d:%s: expect (%q, %v), actual (%q, %v)", i, c.name, c.expectOut, c.expectErr, out, err)
+		}
+	}
+
+	expectCalls := [][]string{{"/Engine/Build.sh", "Game"}, {"reg", "query", "HKCU"}, {"query"}, {"ls"}}
+	if !reflect.DeepEqual(fake.Calls, expectCalls) {
+		t.Errorf("expect calls %q, actual %q", expectCalls, fake.Calls)
+	}
+}
+
+// TestFakeStream 测试 Fake 将预设的输出逐行交给 LineHandler。
+func TestFakeStream(t *testing.T) {
+	type line struct {
+		text  string
+		isErr bool
+	}
+
+	errExit := errors.New("exit status 1")
+	cases := []struct {
+		name      string
+		resp      *Response
+		expect    []line
+		expectErr error
+	}{
+		{
+			name:   "stdout then stderr",
+			resp:   &Response{StdOut: "a\nb\n", StdErr: "c\n"},
+			expect: []line{{"a", false}, {"b", false}, {"c", true}},
+		},
+		{
+			name:   "crlf without trailing newline",
+			resp:   &Response{StdOut: "a\r\nb"},
+			expect: []line{{"a", false}, {"b", false}},
+		},
+		{
+			name:      "empty output with error",
+			resp:      &Response{Err: errExit},
+			expect:    nil,
+			expectErr: errExit,
+		},
+	}
+
+	for i, c := range cases {
+		fake := &Fake{Responses: []*Response{c.resp}}
+		var actual []line
+		err := fake.Stream(func(text string, isErr bool) {
+			actual = append(actual, line{text, isErr})
+		}, "any")
+
+		if err != c.expectErr || !reflect.DeepEqual(actual, c.expect) {
+			t.Errorf("%d:%s: expect (%v, %v), actual (%v, %v)", i, c.name, c.expect, c.expectErr, actual, err)
+		}
+	}
+}
+
+// TestUse 测试 Use 替换和恢复 New 返回的执行器。
+func TestUse(t *testing.T) {
+	fake := &Fake{}
+	restore := Use(fake)
+	for _, kind := range []Kind{KindPowerShell, KindShell, KindDirect, Kind(100)} {
+		e, err := New(kind)
+		if err != nil || e != Executor(fake) {
+			t.Errorf("%s: expect the fake executor, actual %v, %v", kind, e, err)
+		}
+	}
+
+	restore()
+	if e, err := New(KindDirect); err != nil || e == Executor(fake) {
+		t.Errorf("expect a direct executor after restore, actual %v, %v", e, err)
+	}
+
+	if _, err := New(Kind(100)); err == nil {
+		t.Errorf("expect error for unknown kind after restore")
+	}
+}
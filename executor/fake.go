@@ -0,0 +1,65 @@
+package executor
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Response 是 Fake 执行器预设的返回结果。
+type Response struct {
+	Match  string // 命令中包含该字符串时返回此结果，为空时匹配任意命令
+	StdOut string
+	StdErr string
+	Err    error
+}
+
+// Fake 是用于测试的执行器，记录所有执行过的命令，并按预设的 Responses 返回结果。
+type Fake struct {
+	Responses []*Response
+	Calls     [][]string
+}
+
+func (f *Fake) match(args []string) (*Response, error) {
+	f.Calls = append(f.Calls, append([]string(nil), args...))
+	cmd := strings.Join(args, " ")
+	for _, resp := range f.Responses {
+		if strings.Contains(cmd, resp.Match) {
+			return resp, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no scripted response for command: %s", cmd)
+}
+
+// Execute 记录命令，并返回第一个匹配的预设结果，没有匹配的结果时返回错误。
+func (f *Fake) Execute(args ...string) (stdOut string, stdErr string, err error) {
+	resp, err := f.match(args)
+	if err != nil {
+		return "", "", err
+	}
+
+	return resp.StdOut, resp.StdErr, resp.Err
+}
+
+// Stream 记录命令，并将第一个匹配的预设结果逐行交给 onLine，先输出 StdOut，再输出 StdErr。
+func (f *Fake) Stream(onLine LineHandler, args ...string) error {
+	resp, err := f.match(args)
+	if err != nil {
+		return err
+	}
+
+	for _, out := range []struct {
+		text  string
+		isErr bool
+	}{{resp.StdOut, false}, {resp.StdErr, true}} {
+		if len(out.text) == 0 {
+			continue
+		}
+
+		for _, line := range strings.Split(strings.TrimSuffix(out.text, "\n"), "\n") {
+			onLine(strings.TrimSuffix(line, "\r"), out.isErr)
+		}
+	}
+
+	return resp.Err
+}
@@ -0,0 +1,38 @@
+package executor
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+// PowerShell 是 PowerShell 对应的执行器。
+type PowerShell struct {
+	powerShell string
+}
+
+// NewPowerShell 创建一个新的 PowerShell 执行器，优先使用 Windows PowerShell，找不到时使用 pwsh。
+func NewPowerShell() (*PowerShell, error) {
+	ps, err := exec.LookPath("powershell.exe")
+	if err != nil {
+		var pwshErr error
+		if ps, pwshErr = exec.LookPath("pwsh"); pwshErr != nil {
+			return nil, fmt.Errorf("PowerShell not found: %w", err)
+		}
+	}
+
+	return &PowerShell{
+		powerShell: ps,
+	}, nil
+}
+
+// Execute 执行一段 PowerShell 命令。
+func (p *PowerShell) Execute(args ...string) (stdOut string, stdErr string, err error) {
+	args = append([]string{"-NoProfile", "-NonInteractive"}, args...)
+	return run(exec.Command(p.powerShell, args...))
+}
+
+// Stream 执行一段 PowerShell 命令，并逐行处理输出。
+func (p *PowerShell) Stream(onLine LineHandler, args ...string) error {
+	args = append([]string{"-NoProfile", "-NonInteractive"}, args...)
+	return stream(exec.Command(p.powerShell, args...), onLine)
+}
@@ -0,0 +1,34 @@
+package executor
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Shell 是 POSIX shell 对应的执行器。
+type Shell struct {
+	shell string
+}
+
+// NewShell 创建一个新的 POSIX shell 执行器。
+func NewShell() (*Shell, error) {
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		return nil, fmt.Errorf("POSIX shell not found: %w", err)
+	}
+
+	return &Shell{
+		shell: sh,
+	}, nil
+}
+
+// Execute 执行一段 shell 脚本，多个 args 之间用空格连接。
+func (s *Shell) Execute(args ...string) (stdOut string, stdErr string, err error) {
+	return run(exec.Command(s.shell, "-c", strings.Join(args, " ")))
+}
+
+// Stream 执行一段 shell 脚本，并逐行处理输出。
+func (s *Shell) Stream(onLine LineHandler, args ...string) error {
+	return stream(exec.Command(s.shell, "-c", strings.Join(args, " ")), onLine)
+}
@@ -0,0 +1,110 @@
+package gencmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/zhiruili/urem/core"
+	"github.com/zhiruili/urem/unreal"
+)
+
+// clangDbCacheFileName 是 Intermediate/urem 目录下记录上次生成 clang database 的输入的缓存文件名。
+const clangDbCacheFileName = "clangdb_cache.json"
+
+// clangDbCache 记录上次调用 UBT 生成 clang database 时的输入的 hash 和生成的文件。
+type clangDbCache struct {
+	InputHash string // 构建输入文件的内容、引擎版本和生成选项的 hash
+	SrcDbPath string // UBT 生成的原始 clang database 的路径
+}
+
+func clangDbCachePath(projectInfo *unreal.ProjectInfo) string {
+	return filepath.Join(projectInfo.ProjectUremIntermediateDir(), clangDbCacheFileName)
+}
+
+// hashBuildInputs 计算所有影响 UBT 生成结果的输入的 hash，包括构建输入文件的内容、引擎版本以及 options 中的生成选项。
+func hashBuildInputs(projectInfo *unreal.ProjectInfo, engine *unreal.EngineInfo, options ...string) (string, error) {
+	files, err := projectInfo.FindBuildInputFiles()
+	if err != nil {
+		return "", fmt.Errorf("find build input files: %w", err)
+	}
+
+	h := sha256.New()
+	fmt.Fprintf(h, "engine %s %s\n", engine.RealVersion(), filepath.Clean(engine.InstallPath))
+	for _, option := range options {
+		fmt.Fprintf(h, "option %s\n", option)
+	}
+
+	for _, file := range files {
+		f, err := os.Open(file)
+		if err != nil {
+			return "", fmt.Errorf("open build input file: %w", err)
+		}
+
+		fileHash := sha256.New()
+		_, err = io.Copy(fileHash, f)
+		f.Close()
+		if err != nil {
+			return "", fmt.Errorf("read build input file %s: %w", file, err)
+		}
+
+		rel, err := filepath.Rel(projectInfo.ProjectDir(), file)
+		if err != nil {
+			rel = file
+		}
+
+		fmt.Fprintf(h, "file %s %x\n", filepath.ToSlash(rel), fileHash.Sum(nil))
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// loadClangDbCache 读取缓存，缓存文件不存在时返回 nil。
+func loadClangDbCache(projectInfo *unreal.ProjectInfo) (*clangDbCache, error) {
+	content, err := os.ReadFile(clangDbCachePath(projectInfo))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("open clang database cache: %w", err)
+	}
+
+	var cache clangDbCache
+	if err := json.Unmarshal(content, &cache); err != nil {
+		return nil, fmt.Errorf("unmarshal clang database cache: %w", err)
+	}
+
+	return &cache, nil
+}
+
+// saveClangDbCache 写入缓存。
+func saveClangDbCache(projectInfo *unreal.ProjectInfo, cache *clangDbCache) error {
+	content, err := json.MarshalIndent(cache, "", "\t")
+	if err != nil {
+		return fmt.Errorf("marshal clang database cache: %w", err)
+	}
+
+	cachePath := clangDbCachePath(projectInfo)
+	if err := os.MkdirAll(filepath.Dir(cachePath), os.ModePerm); err != nil {
+		return fmt.Errorf("create dir of %s: %w", cachePath, err)
+	}
+
+	core.LogD("write file to %s", cachePath)
+	return os.WriteFile(cachePath, content, 0644)
+}
+
+// isValid 检查缓存是否和当前的输入一致，并且缓存的 clang database 仍然存在。
+func (cache *clangDbCache) isValid(inputHash string) bool {
+	if cache == nil || cache.InputHash != inputHash {
+		return false
+	}
+
+	_, err := os.Stat(cache.SrcDbPath)
+	return err == nil
+}
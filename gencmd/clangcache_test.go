@@ -0,0 +1,69 @@
+package gencmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/zhiruili/urem/unreal"
+)
+
+// TestHashBuildInputs 测试只有影响 UBT 的输入变化时 hash 才会变化。
+func TestHashBuildInputs(t *testing.T) {
+	projectDir := t.TempDir()
+	projectInfo := &unreal.ProjectInfo{ProjectFilePath: filepath.Join(projectDir, "Game.uproject")}
+	engine := &unreal.EngineInfo{Version: "5.3", InstallPath: "/opt/UE_5.3"}
+	writeFile := func(file string, content string) {
+		path := filepath.Join(projectDir, file)
+		if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writeFile("Game.uproject", "{}")
+	writeFile("Source/Game.Target.cs", "target")
+	writeFile("Source/Game/Game.Build.cs", "module")
+	writeFile("Plugins/Tools/Tools.uplugin", "{}")
+
+	hash := func(options ...string) string {
+		h, err := hashBuildInputs(projectInfo, engine, options...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return h
+	}
+
+	base := hash("clangdb", "Linux")
+	cases := []struct {
+		name    string
+		change  func()
+		options []string
+		changed bool
+	}{
+		{name: "nothing changed", change: func() {}, changed: false},
+		{name: "source changed", change: func() { writeFile("Source/Game/Private/Game.cpp", "code") }, changed: false},
+		{name: "intermediate changed", change: func() { writeFile("Plugins/Tools/Intermediate/A.Build.cs", "x") }, changed: false},
+		{name: "option changed", change: func() {}, options: []string{"clangdb", "Win64"}, changed: true},
+		{name: "build file changed", change: func() { writeFile("Source/Game/Game.Build.cs", "module2") }, changed: true},
+		{name: "plugin added", change: func() { writeFile("Plugins/Other/Other.uplugin", "{}") }, changed: true},
+	}
+
+	for i, c := range cases {
+		c.change()
+		options := c.options
+		if options == nil {
+			options = []string{"clangdb", "Linux"}
+		}
+
+		actual := hash(options...)
+		if (actual != base) != c.changed {
+			t.Errorf("%d:%s: expect changed %v, actual %v", i, c.name, c.changed, actual != base)
+		}
+
+		base = hash("clangdb", "Linux")
+	}
+}
@@ -0,0 +1,192 @@
+package gencmd
+
+import (
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/iancoleman/orderedmap"
+	"github.com/zhiruili/urem/core"
+	"github.com/zhiruili/urem/unreal"
+)
+
+// 过滤规则的前缀，没有前缀的规则是匹配文件路径的 glob。
+const (
+	filterModulePrefix = "module:"
+	filterPluginPrefix = "plugin:"
+)
+
+// globToRegexp 将 glob 转换为正则表达式，* 和 ? 不匹配路径分隔符，** 匹配任意多级目录。
+func globToRegexp(glob string) (*regexp.Regexp, error) {
+	var buf strings.Builder
+	buf.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch c {
+		case '*':
+			if i+1 < len(glob) && glob[i+1] == '*' {
+				i++
+				if i+1 < len(glob) && glob[i+1] == '/' {
+					// **/ 可以匹配零级目录
+					i++
+					buf.WriteString("(.*/)?")
+				} else {
+					buf.WriteString(".*")
+				}
+			} else {
+				buf.WriteString("[^/]*")
+			}
+		case '?':
+			buf.WriteString("[^/]")
+		default:
+			buf.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	buf.WriteString("$")
+	return regexp.Compile(buf.String())
+}
+
+// filterRule 是一条过滤规则，可以按照 module 名、插件名或者文件路径匹配。
+type filterRule struct {
+	module string         // module 名的 glob
+	plugin string         // 插件名的 glob
+	file   *regexp.Regexp // 文件路径的 glob
+}
+
+func newFilterRule(s string) (*filterRule, error) {
+	if strings.HasPrefix(s, filterModulePrefix) {
+		return &filterRule{module: strings.TrimPrefix(s, filterModulePrefix)}, nil
+	}
+
+	if strings.HasPrefix(s, filterPluginPrefix) {
+		return &filterRule{plugin: strings.TrimPrefix(s, filterPluginPrefix)}, nil
+	}
+
+	re, err := globToRegexp(filepath.ToSlash(s))
+	if err != nil {
+		return nil, err
+	}
+
+	return &filterRule{file: re}, nil
+}
+
+// matchName 不区分大小写地匹配名字。
+func matchName(pattern string, name string) bool {
+	if len(name) == 0 {
+		return false
+	}
+
+	ok, _ := path.Match(strings.ToLower(pattern), strings.ToLower(name))
+	return ok
+}
+
+// entryInfo 是 clang database 中一项对应的源文件信息。
+type entryInfo struct {
+	file      string // 源文件的绝对路径
+	relFile   string // 相对于工程目录的路径，使用 / 分隔，不在工程目录下时是绝对路径
+	inProject bool   // 是否在工程目录下
+	module    string
+	moduleDir string
+	plugin    string
+}
+
+func (r *filterRule) match(info *entryInfo) bool {
+	switch {
+	case len(r.module) != 0:
+		return matchName(r.module, info.module)
+	case len(r.plugin) != 0:
+		return matchName(r.plugin, info.plugin)
+	}
+
+	return r.file.MatchString(info.relFile) || r.file.MatchString(filepath.ToSlash(info.file))
+}
+
+// entryFilter 根据 include 和 exclude 规则过滤 clang database 中的项。
+// include 为空时包含所有项，否则只包含匹配任意一条 include 规则的项，之后再排除匹配任意一条 exclude 规则的项。
+type entryFilter struct {
+	includes []*filterRule
+	excludes []*filterRule
+}
+
+func parseFilterRules(argName string, ss []string) ([]*filterRule, error) {
+	var rules []*filterRule
+	for _, s := range ss {
+		rule, err := newFilterRule(s)
+		if err != nil {
+			return nil, core.IllegalArgErrorf(argName, "illegal filter %s: %s", s, err.Error())
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+func newEntryFilter(includes []string, excludes []string) (*entryFilter, error) {
+	includeRules, err := parseFilterRules("Include", includes)
+	if err != nil {
+		return nil, err
+	}
+
+	excludeRules, err := parseFilterRules("Exclude", excludes)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entryFilter{includes: includeRules, excludes: excludeRules}, nil
+}
+
+func (f *entryFilter) empty() bool {
+	return len(f.includes) == 0 && len(f.excludes) == 0
+}
+
+func (f *entryFilter) match(info *entryInfo) bool {
+	included := len(f.includes) == 0
+	for _, rule := range f.includes {
+		if rule.match(info) {
+			included = true
+			break
+		}
+	}
+
+	if !included {
+		return false
+	}
+
+	for _, rule := range f.excludes {
+		if rule.match(info) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// entryFile 获取 clang database 中一项的源文件的绝对路径。
+func entryFile(entry *orderedmap.OrderedMap) string {
+	value, _ := entry.Get("file")
+	file, _ := value.(string)
+	if len(file) == 0 || filepath.IsAbs(file) {
+		return file
+	}
+
+	value, _ = entry.Get("directory")
+	dir, _ := value.(string)
+	return filepath.Join(dir, file)
+}
+
+// newEntryInfo 获取 clang database 中一项的源文件信息。
+func newEntryInfo(entry *orderedmap.OrderedMap, projectDir string, locator *unreal.ModuleLocator) *entryInfo {
+	info := &entryInfo{file: entryFile(entry)}
+	info.relFile = filepath.ToSlash(info.file)
+	if rel, err := filepath.Rel(projectDir, info.file); err == nil && !strings.HasPrefix(rel, "..") {
+		info.relFile = filepath.ToSlash(rel)
+		info.inProject = true
+	}
+
+	info.module, info.moduleDir = locator.Module(info.file)
+	info.plugin, _ = locator.Plugin(info.file)
+	return info
+}
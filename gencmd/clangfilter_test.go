@@ -0,0 +1,72 @@
+package gencmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/iancoleman/orderedmap"
+	"github.com/zhiruili/urem/unreal"
+)
+
+// TestEntryFilter 测试 entryFilter 按照 module、插件和路径过滤。
+func TestEntryFilter(t *testing.T) {
+	projectDir := t.TempDir()
+	for _, file := range []string{
+		"Source/Game/Game.Build.cs",
+		"Source/Game/Private/Game.cpp",
+		"Source/GameEditor/GameEditor.Build.cs",
+		"Source/GameEditor/Private/GameEditor.cpp",
+		"Plugins/Tools/Tools.uplugin",
+		"Plugins/Tools/Source/ToolsCore/ToolsCore.Build.cs",
+		"Plugins/Tools/Source/ToolsCore/Private/ToolsCore.cpp",
+	} {
+		path := filepath.Join(projectDir, file)
+		if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	files := []string{
+		"Source/Game/Private/Game.cpp",
+		"Source/GameEditor/Private/GameEditor.cpp",
+		"Plugins/Tools/Source/ToolsCore/Private/ToolsCore.cpp",
+	}
+
+	cases := []struct {
+		name     string
+		includes []string
+		excludes []string
+		expect   []bool
+	}{
+		{name: "no filter", expect: []bool{true, true, true}},
+		{name: "include module", includes: []string{"module:game"}, expect: []bool{true, false, false}},
+		{name: "include module glob", includes: []string{"module:Game*"}, expect: []bool{true, true, false}},
+		{name: "exclude plugin", excludes: []string{"plugin:Tools"}, expect: []bool{true, true, false}},
+		{name: "include glob", includes: []string{"Plugins/**"}, expect: []bool{false, false, true}},
+		{name: "exclude glob", excludes: []string{"**/*Editor.cpp"}, expect: []bool{true, false, true}},
+		{name: "include and exclude", includes: []string{"Source/**/*.cpp"}, excludes: []string{"module:GameEditor"}, expect: []bool{true, false, false}},
+	}
+
+	locator := unreal.NewModuleLocator()
+	for i, c := range cases {
+		filter, err := newEntryFilter(c.includes, c.excludes)
+		if err != nil {
+			t.Fatalf("%d:%s: %s", i, c.name, err)
+		}
+
+		for j, file := range files {
+			entry := orderedmap.New()
+			entry.Set("file", file)
+			entry.Set("directory", projectDir)
+			actual := filter.match(newEntryInfo(entry, projectDir, locator))
+			if actual != c.expect[j] {
+				t.Errorf("%d:%s: %s expect %v, actual %v", i, c.name, file, c.expect[j], actual)
+			}
+		}
+	}
+}
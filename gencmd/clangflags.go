@@ -0,0 +1,162 @@
+package gencmd
+
+import (
+	"strings"
+
+	"github.com/zhiruili/urem/core"
+)
+
+// compile_commands.json 中编译参数的风格。
+const (
+	flagStyleAuto = "auto" // 根据平台选择，Windows 平台使用 msvc，其他平台使用 gnu
+	flagStyleMsvc = "msvc" // 编译器是 cl 或者 clang-cl，保留 MSVC 风格的参数
+	flagStyleGnu  = "gnu"  // 编译器是 clang，需要将 MSVC 风格的参数转换为 clang 的参数
+)
+
+var availableFlagStyles = []string{flagStyleAuto, flagStyleMsvc, flagStyleGnu}
+
+// msvcFlagRules 是 msvc 风格下的默认转换规则，只删除 clangd 无法使用的 PCH 参数。
+var msvcFlagRules = map[string]string{
+	"/Yu": "",
+	"/Yc": "",
+	"/Fp": "",
+}
+
+// gnuFlagRules 是 gnu 风格下的默认转换规则，key 是 MSVC 参数的前缀，value 是替换前缀的内容，为空时删除参数。
+// 参考 clang-cl 的参数说明：https://clang.llvm.org/docs/UsersManual.html#clang-cl
+var gnuFlagRules = map[string]string{
+	"/D":                  "-D",
+	"/U":                  "-U",
+	"/I":                  "-I",
+	"/FI":                 "-include",
+	"/std:":               "-std=",
+	"/std:c++latest":      "-std=c++2b",
+	"/source-charset:":    "-finput-charset=",
+	"/execution-charset:": "-fexec-charset=",
+	"/EHsc":               "-fexceptions",
+	"/GR-":                "-fno-rtti",
+	"/GR":                 "-frtti",
+	"/TP":                 "-xc++",
+	"/Yu":                 "",
+	"/Yc":                 "",
+	"/Fp":                 "",
+	"/Fo":                 "",
+	"/Fd":                 "",
+	"/FC":                 "",
+	"/Zc:":                "",
+	"/Zm":                 "",
+	"/Z7":                 "",
+	"/Zi":                 "",
+	"/Zo":                 "",
+	"/Zp":                 "",
+	"/O":                  "",
+	"/G":                  "",
+	"/W":                  "",
+	"/wd":                 "",
+	"/we":                 "",
+	"/MD":                 "",
+	"/MT":                 "",
+	"/c":                  "",
+	"/nologo":             "",
+	"/bigobj":             "",
+	"/fastfail":           "",
+	"/errorReport:":       "",
+	"/fp:":                "",
+}
+
+// gnuSeparateValueFlags 是 gnu 风格下值作为单独参数的选项，它们的值可能是以 / 开头的路径，不能被转换。
+var gnuSeparateValueFlags = []string{"-I", "-D", "-U", "-o", "-x", "-include", "-include-pch", "-isystem", "-iquote", "-MF"}
+
+// flagTranslator 根据规则将 MSVC 风格的参数转换为 clangd 可以使用的参数。
+type flagTranslator struct {
+	rules map[string]string
+}
+
+// newFlagTranslator 创建指定风格的参数转换器，custom 中的规则会覆盖默认规则。
+func newFlagTranslator(style string, custom map[string]string) *flagTranslator {
+	defaults := msvcFlagRules
+	if style == flagStyleGnu {
+		defaults = gnuFlagRules
+	}
+
+	rules := make(map[string]string, len(defaults)+len(custom))
+	for prefix, replacement := range defaults {
+		rules[prefix] = replacement
+	}
+
+	for prefix, replacement := range custom {
+		rules[prefix] = replacement
+	}
+
+	return &flagTranslator{rules: rules}
+}
+
+// isMsvcCompiler 检查编译器是否是 cl 或者 clang-cl，只有它们的参数是 MSVC 风格的。
+func isMsvcCompiler(compiler string) bool {
+	name := strings.ToLower(compiler[strings.LastIndexAny(compiler, `/\`)+1:])
+	name = strings.TrimSuffix(name, ".exe")
+	return name == "cl" || name == "clang-cl"
+}
+
+// translateFlag 转换单个参数，使用最长的匹配前缀，返回 false 表示参数应被删除。
+// msvc 为 false 时以 / 开头的参数是路径，不使用以 / 开头的规则。
+func (t *flagTranslator) translateFlag(flag string, msvc bool) (string, bool) {
+	matched := ""
+	found := false
+	for prefix := range t.rules {
+		if !msvc && strings.HasPrefix(prefix, "/") {
+			continue
+		}
+
+		if strings.HasPrefix(flag, prefix) && len(prefix) >= len(matched) {
+			matched = prefix
+			found = true
+		}
+	}
+
+	if !found {
+		return flag, true
+	}
+
+	replacement := t.rules[matched]
+	if len(replacement) == 0 {
+		return "", false
+	}
+
+	return replacement + flag[len(matched):], true
+}
+
+// translate 转换参数列表，skip 中的参数（如源文件路径）保持不变。
+// msvc 表示参数是否是 MSVC 风格的，不是时只使用不以 / 开头的规则，避免改写 /Users、/Data 这样的路径。
+func (t *flagTranslator) translate(args []string, skip string, msvc bool) []string {
+	result := make([]string, 0, len(args))
+	for _, arg := range args {
+		if arg == skip || (len(result) > 0 && core.StrContains(gnuSeparateValueFlags, result[len(result)-1])) {
+			result = append(result, arg)
+			continue
+		}
+
+		translated, ok := t.translateFlag(arg, msvc)
+		if !ok {
+			core.LogD("drop flag %s", arg)
+			continue
+		}
+
+		result = append(result, translated)
+	}
+
+	return result
+}
+
+// chooseFlagStyle 确定参数风格，auto 时根据目标平台选择。
+func chooseFlagStyle(style string, platform string) string {
+	if len(style) != 0 && style != flagStyleAuto {
+		return style
+	}
+
+	if strings.HasPrefix(platform, "Win") {
+		return flagStyleMsvc
+	}
+
+	return flagStyleGnu
+}
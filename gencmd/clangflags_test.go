@@ -0,0 +1,152 @@
+package gencmd
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/iancoleman/orderedmap"
+)
+
+// TestFlagTranslator 测试 flagTranslator 的参数转换。
+func TestFlagTranslator(t *testing.T) {
+	cases := []struct {
+		name   string
+		style  string
+		custom map[string]string
+		msvc   bool
+		args   []string
+		skip   string
+		expect []string
+	}{
+		{
+			name:   "gnu translate",
+			style:  flagStyleGnu,
+			msvc:   true,
+			args:   []string{"/DWITH_EDITOR=1", "/I", "/Engine/Source", "/std:c++20", "/FIDefinitions.h", "/source-charset:utf-8"},
+			expect: []string{"-DWITH_EDITOR=1", "-I", "/Engine/Source", "-std=c++20", "-includeDefinitions.h", "-finput-charset=utf-8"},
+		},
+		{
+			name:   "gnu drop",
+			style:  flagStyleGnu,
+			msvc:   true,
+			args:   []string{"/Zc:inline", "/YuSharedPCH.h", "/FpSharedPCH.pch", "/wd4819", "/Ox", "-Wall"},
+			expect: []string{"-Wall"},
+		},
+		{
+			name:   "gnu longest prefix",
+			style:  flagStyleGnu,
+			msvc:   true,
+			args:   []string{"/std:c++latest", "/GR-", "/GR", "/Gy"},
+			expect: []string{"-std=c++2b", "-fno-rtti", "-frtti"},
+		},
+		{
+			name:   "gnu keep paths",
+			style:  flagStyleGnu,
+			msvc:   true,
+			args:   []string{"-o", "/Game/Out.o", "-include", "/Game/PCH.h", "/Game/Source/A.cpp"},
+			skip:   "/Game/Source/A.cpp",
+			expect: []string{"-o", "/Game/Out.o", "-include", "/Game/PCH.h", "/Game/Source/A.cpp"},
+		},
+		{
+			name:   "msvc only drop pch",
+			style:  flagStyleMsvc,
+			msvc:   true,
+			args:   []string{"/DWITH_EDITOR=1", "/YuSharedPCH.h", "/FpSharedPCH.pch", "/Zc:inline"},
+			expect: []string{"/DWITH_EDITOR=1", "/Zc:inline"},
+		},
+		{
+			name:   "custom rules",
+			style:  flagStyleGnu,
+			custom: map[string]string{"/Zc:": "/Zc:", "/bigobj": "-fbigobj", "-Werror": ""},
+			msvc:   true,
+			args:   []string{"/Zc:inline", "/bigobj", "-Werror", "/DA"},
+			expect: []string{"/Zc:inline", "-fbigobj", "-DA"},
+		},
+		{
+			name:   "gnu compiler keeps posix paths",
+			style:  flagStyleGnu,
+			custom: map[string]string{"-Werror": ""},
+			args:   []string{"-Xclang", "/Data/Game/PCH.h", "/Include/A.h", "/Users/me/Fo.o", "/GR/B.cpp", "-Werror", "-DA"},
+			expect: []string{"-Xclang", "/Data/Game/PCH.h", "/Include/A.h", "/Users/me/Fo.o", "/GR/B.cpp", "-DA"},
+		},
+	}
+
+	for i, c := range cases {
+		actual := newFlagTranslator(c.style, c.custom).translate(c.args, c.skip, c.msvc)
+		if !reflect.DeepEqual(actual, c.expect) {
+			t.Errorf("%d:%s: expect %v, actual %v", i, c.name, c.expect, actual)
+		}
+	}
+}
+
+// TestChooseFlagStyle 测试 chooseFlagStyle 函数。
+func TestChooseFlagStyle(t *testing.T) {
+	cases := []struct {
+		name     string
+		style    string
+		platform string
+		expect   string
+	}{
+		{name: "auto windows", style: "", platform: "Win64", expect: flagStyleMsvc},
+		{name: "auto linux", style: flagStyleAuto, platform: "Linux", expect: flagStyleGnu},
+		{name: "auto mac", style: "", platform: "Mac", expect: flagStyleGnu},
+		{name: "explicit", style: flagStyleMsvc, platform: "Linux", expect: flagStyleMsvc},
+	}
+
+	for i, c := range cases {
+		if actual := chooseFlagStyle(c.style, c.platform); actual != c.expect {
+			t.Errorf("%d:%s: expect %s, actual %s", i, c.name, c.expect, actual)
+		}
+	}
+}
+
+// TestTranslateEntry 测试转换 compile_commands.json 中使用 arguments 和 command 字段的项。
+func TestTranslateEntry(t *testing.T) {
+	cases := []struct {
+		name   string
+		style  string
+		split  string
+		entry  string
+		expect string
+	}{
+		{
+			name:   "msvc command",
+			style:  flagStyleGnu,
+			split:  flagStyleMsvc,
+			entry:  `{"file":"A.cpp","command":"\"C:\\VS\\cl.exe\" /DA=1 /I \"C:\\Game Dir\" /YuPCH.h A.cpp","directory":"C:\\Game"}`,
+			expect: `{"file":"A.cpp","arguments":["C:\\VS\\cl.exe","-DA=1","-I","C:\\Game Dir","A.cpp","@clangd_args"],"directory":"C:\\Game"}`,
+		},
+		{
+			name:   "clang command with posix paths",
+			style:  flagStyleGnu,
+			split:  flagStyleGnu,
+			entry:  `{"file":"/Data/A.cpp","command":"/usr/bin/clang++ -I/Data/Include /Data/A.cpp","directory":"/Data"}`,
+			expect: `{"file":"/Data/A.cpp","arguments":["/usr/bin/clang++","-I/Data/Include","/Data/A.cpp","@clangd_args"],"directory":"/Data"}`,
+		},
+		{
+			name:   "clang-cl arguments",
+			style:  flagStyleGnu,
+			split:  flagStyleGnu,
+			entry:  `{"file":"A.cpp","arguments":["clang-cl","/DA","/Zi","A.cpp"]}`,
+			expect: `{"file":"A.cpp","arguments":["clang-cl","-DA","A.cpp","@clangd_args"]}`,
+		},
+	}
+
+	for i, c := range cases {
+		entry := orderedmap.New()
+		if err := json.Unmarshal([]byte(c.entry), entry); err != nil {
+			t.Fatal(err)
+		}
+
+		translateEntry(entry, newFlagTranslator(c.style, nil), argsSplitter(c.split), "@clangd_args")
+		actual, err := json.Marshal(entry)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(actual) != c.expect {
+			t.Errorf("%d:%s: expect %s, actual %s", i, c.name, c.expect, actual)
+		}
+	}
+}
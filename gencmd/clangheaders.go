@@ -0,0 +1,195 @@
+package gencmd
+
+import (
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/iancoleman/orderedmap"
+	"github.com/zhiruili/urem/core"
+	"github.com/zhiruili/urem/unreal"
+)
+
+// headerDirs 是 module 中存放头文件的目录。
+var headerDirs = []string{"Public", "Private", "Classes"}
+
+// headerExts 是需要补充 clang database 项的头文件后缀。
+var headerExts = []string{".h", ".hpp", ".hh", ".hxx"}
+
+// headerSynthesizer 为 module 中的头文件生成 clang database 中的项，
+// 因为 UBT 生成的 clang database 只包含源文件，clangd 猜测的头文件编译参数经常缺少 module 的 include 路径和宏定义。
+type headerSynthesizer struct {
+	projectDir string
+	flagStyle  string
+	filter     *entryFilter
+	locator    *unreal.ModuleLocator
+}
+
+// findHeaders 查找 module 目录下 Public、Private 和 Classes 中的所有头文件。
+func findHeaders(moduleDir string) []string {
+	var headers []string
+	for _, dir := range headerDirs {
+		_ = filepath.WalkDir(filepath.Join(moduleDir, dir), func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				// 目录不存在时忽略
+				return nil
+			}
+
+			if !d.IsDir() && core.StrContains(headerExts, strings.ToLower(filepath.Ext(p))) {
+				headers = append(headers, p)
+			}
+
+			return nil
+		})
+	}
+
+	sort.Strings(headers)
+	return headers
+}
+
+// fileStem 获取去掉后缀的文件名。
+func fileStem(file string) string {
+	base := filepath.Base(file)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+// matchScore 计算源文件和头文件的匹配程度，同名的文件最匹配，其次是目录层级重合最多的文件。
+func matchScore(header string, source string) int {
+	headerParts := strings.Split(filepath.ToSlash(filepath.Dir(header)), "/")
+	sourceParts := strings.Split(filepath.ToSlash(filepath.Dir(source)), "/")
+	score := 0
+	for score < len(headerParts) && score < len(sourceParts) && headerParts[score] == sourceParts[score] {
+		score++
+	}
+
+	if strings.EqualFold(fileStem(header), fileStem(source)) {
+		score += 1000
+	}
+
+	return score
+}
+
+// headerLanguageFlag 获取将头文件作为 C++ 头文件处理的参数。
+func (s *headerSynthesizer) headerLanguageFlag() string {
+	if s.flagStyle == flagStyleMsvc {
+		return "/TP"
+	}
+
+	return "-xc++-header"
+}
+
+// headerArguments 获取源文件的项中的参数，找不到源文件时返回 false，
+// 这时源文件通常在没有展开的 response file 中，无法替换为头文件。
+func (s *headerSynthesizer) headerArguments(source *orderedmap.OrderedMap, sourceFile string) ([]string, bool) {
+	value, _ := source.Get("file")
+	sourceFileRaw, _ := value.(string)
+	args, _ := entryArguments(source, argsSplitter(s.flagStyle))
+	for _, arg := range args {
+		if arg == sourceFileRaw || arg == sourceFile {
+			return args, true
+		}
+	}
+
+	return args, false
+}
+
+// newHeaderEntry 复制源文件的项，将其中的源文件替换为头文件，源文件不在参数中时返回 nil。
+func (s *headerSynthesizer) newHeaderEntry(source *orderedmap.OrderedMap, sourceFile string, header string) *orderedmap.OrderedMap {
+	args, ok := s.headerArguments(source, sourceFile)
+	if !ok {
+		return nil
+	}
+
+	value, _ := source.Get("file")
+	sourceFileRaw, _ := value.(string)
+	newArgs := make([]interface{}, 0, len(args)+1)
+	for i, arg := range args {
+		if arg == sourceFileRaw || arg == sourceFile {
+			arg = header
+		}
+
+		newArgs = append(newArgs, arg)
+		if i == 0 {
+			newArgs = append(newArgs, s.headerLanguageFlag())
+		}
+	}
+
+	entry := orderedmap.New()
+	for _, key := range source.Keys() {
+		switch key {
+		case "file":
+			entry.Set(key, header)
+		case "arguments", "command":
+			entry.Set("arguments", newArgs)
+		case "output":
+			// 头文件没有编译产物
+		default:
+			value, _ := source.Get(key)
+			entry.Set(key, value)
+		}
+	}
+
+	return entry
+}
+
+// synthesize 为 entries 中出现的每个 module 的头文件添加项，使用同一个 module 中最匹配的源文件的编译参数。
+// 已经存在的项和被过滤掉的头文件不会添加，源文件不在参数中（如在没有展开的 response file 中）的项不会被使用。
+func (s *headerSynthesizer) synthesize(entries []orderedmap.OrderedMap, infos []*entryInfo) ([]orderedmap.OrderedMap, []*entryInfo) {
+	var moduleDirs []string
+	moduleSources := map[string][]int{}
+	existed := map[string]bool{}
+	skipped := 0
+	for i, info := range infos {
+		existed[filepath.Clean(info.file)] = true
+		if len(info.moduleDir) == 0 {
+			continue
+		}
+
+		if _, ok := s.headerArguments(&entries[i], info.file); !ok {
+			core.LogD("source %s is not in the arguments, skip it for headers", info.file)
+			skipped++
+			continue
+		}
+
+		if _, ok := moduleSources[info.moduleDir]; !ok {
+			moduleDirs = append(moduleDirs, info.moduleDir)
+		}
+
+		moduleSources[info.moduleDir] = append(moduleSources[info.moduleDir], i)
+	}
+
+	if skipped != 0 {
+		core.LogE("warning: %d entries do not contain their source file in the arguments and are not used for headers, "+
+			"try --expand-rsp", skipped)
+	}
+
+	for _, moduleDir := range moduleDirs {
+		sources := moduleSources[moduleDir]
+		for _, header := range findHeaders(moduleDir) {
+			if existed[filepath.Clean(header)] {
+				continue
+			}
+
+			best := sources[0]
+			bestScore := -1
+			for _, i := range sources {
+				if score := matchScore(header, infos[i].file); score > bestScore {
+					best, bestScore = i, score
+				}
+			}
+
+			entry := s.newHeaderEntry(&entries[best], infos[best].file, header)
+			info := newEntryInfo(entry, s.projectDir, s.locator)
+			if !s.filter.match(info) {
+				continue
+			}
+
+			core.LogD("add header %s with flags of %s", header, infos[best].file)
+			entries = append(entries, *entry)
+			infos = append(infos, info)
+		}
+	}
+
+	return entries, infos
+}
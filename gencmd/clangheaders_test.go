@@ -0,0 +1,106 @@
+package gencmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/iancoleman/orderedmap"
+	"github.com/zhiruili/urem/unreal"
+)
+
+// TestHeaderSynthesizer 测试为 module 中的头文件生成 clang database 的项。
+func TestHeaderSynthesizer(t *testing.T) {
+	projectDir := t.TempDir()
+	moduleDir := filepath.Join(projectDir, "Source", "Game")
+	for _, file := range []string{
+		"Game.Build.cs",
+		"Public/Game.h",
+		"Public/Sub/Other.h",
+		"Private/Game.cpp",
+		"Private/Sub/Helper.cpp",
+		"Private/Sub/Helper.h",
+		"Private/Sub/Inline.inl",
+	} {
+		path := filepath.Join(moduleDir, file)
+		if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var entries []orderedmap.OrderedMap
+	db := `[
+		{"file": "Source/Game/Private/Game.cpp", "arguments": ["clang++", "-DGAME", "Source/Game/Private/Game.cpp"], "directory": "` + projectDir + `", "output": "Game.o"},
+		{"file": "Source/Game/Private/Sub/Helper.cpp", "command": "clang++ -DHELPER Source/Game/Private/Sub/Helper.cpp", "directory": "` + projectDir + `"}
+	]`
+	if err := json.Unmarshal([]byte(db), &entries); err != nil {
+		t.Fatal(err)
+	}
+
+	filter, _ := newEntryFilter(nil, []string{"**/Other.h"})
+	locator := unreal.NewModuleLocator()
+	var infos []*entryInfo
+	for i := range entries {
+		infos = append(infos, newEntryInfo(&entries[i], projectDir, locator))
+	}
+
+	synthesizer := &headerSynthesizer{projectDir: projectDir, flagStyle: flagStyleGnu, filter: filter, locator: locator}
+	entries, _ = synthesizer.synthesize(entries, infos)
+
+	gameHeader := filepath.Join(moduleDir, "Public", "Game.h")
+	helperHeader := filepath.Join(moduleDir, "Private", "Sub", "Helper.h")
+	expects := []string{
+		`{"file":"` + helperHeader + `","arguments":["clang++","-xc++-header","-DHELPER","` + helperHeader + `"],"directory":"` + projectDir + `"}`,
+		`{"file":"` + gameHeader + `","arguments":["clang++","-xc++-header","-DGAME","` + gameHeader + `"],"directory":"` + projectDir + `"}`,
+	}
+
+	if len(entries) != 2+len(expects) {
+		t.Fatalf("expect %d entries, actual %d", 2+len(expects), len(entries))
+	}
+
+	for i, expect := range expects {
+		actual, err := json.Marshal(entries[2+i])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(actual) != expect {
+			t.Errorf("%d: expect %s, actual %s", i, expect, actual)
+		}
+	}
+}
+
+// TestHeaderSynthesizerSkipRsp 测试源文件在没有展开的 response file 中时不生成头文件的项。
+func TestHeaderSynthesizerSkipRsp(t *testing.T) {
+	projectDir := t.TempDir()
+	moduleDir := filepath.Join(projectDir, "Source", "Game")
+	for _, file := range []string{"Game.Build.cs", "Public/Game.h", "Private/Game.cpp"} {
+		path := filepath.Join(moduleDir, file)
+		if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var entries []orderedmap.OrderedMap
+	db := `[{"file": "Source/Game/Private/Game.cpp", "arguments": ["clang++", "@Game.cpp.rsp"], "directory": "` + projectDir + `"}]`
+	if err := json.Unmarshal([]byte(db), &entries); err != nil {
+		t.Fatal(err)
+	}
+
+	filter, _ := newEntryFilter(nil, nil)
+	locator := unreal.NewModuleLocator()
+	infos := []*entryInfo{newEntryInfo(&entries[0], projectDir, locator)}
+	synthesizer := &headerSynthesizer{projectDir: projectDir, flagStyle: flagStyleGnu, filter: filter, locator: locator}
+	if entries, _ = synthesizer.synthesize(entries, infos); len(entries) != 1 {
+		t.Errorf("expect no header entries, actual %d entries", len(entries))
+	}
+}
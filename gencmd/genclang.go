@@ -5,36 +5,171 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/iancoleman/orderedmap"
 	"github.com/zhiruili/urem/core"
+	"github.com/zhiruili/urem/infocmd"
 	"github.com/zhiruili/urem/osutil"
 	"github.com/zhiruili/urem/unreal"
 )
 
+// clang database 的生成方式。
+const (
+	generatorAuto    = "auto"    // 引擎支持时使用 clangdb，否则使用 vscode
+	generatorVscode  = "vscode"  // 生成 VS Code 工程，使用其中的 compileCommands_<Project>.json
+	generatorClangDb = "clangdb" // 使用 UBT 的 -mode=GenerateClangDatabase
+)
+
+var availableGenerators = []string{generatorAuto, generatorVscode, generatorClangDb}
+
 // GenClangCmd 是 gen 子命令中负责生成 clang database 的子命令。
 type GenClangCmd struct {
-	Fast        bool   `arg:"-f,--fast"`
-	ProjectFile string `arg:"positional,required"`
+	Fast          bool     `arg:"-f,--fast"`
+	Force         bool     `arg:"--force" help:"run UBT even if no build input changed since last generation"`
+	Generator     string   `arg:"-g,--generator" help:"how to generate the database auto/vscode/clangdb" default:"auto"`
+	Target        string   `arg:"-t,--target" help:"target used by clangdb generator, defaults to the editor target"`
+	Platform      string   `arg:"-p,--platform" help:"platform used by clangdb generator, defaults to the current platform"`
+	Configuration string   `arg:"-c,--config" help:"configuration used by clangdb generator" default:"Development"`
+	ExpandRsp     bool     `arg:"--expand-rsp" help:"inline the arguments in @response-files into the database"`
+	Include       []string `arg:"--include,separate" help:"only keep entries matching module:NAME, plugin:NAME or a path glob"`
+	Exclude       []string `arg:"--exclude,separate" help:"drop entries matching module:NAME, plugin:NAME or a path glob"`
+	SplitByModule bool     `arg:"--split-by-module" help:"also write a database into each module directory of the project"`
+	Headers       bool     `arg:"--headers" help:"add entries for headers in Public/Private/Classes of each module"`
+	ProjectFile   string   `arg:"positional,required"`
+
+	filter *entryFilter
 }
 
-func (cmd *GenClangCmd) refreshClang(projectInfo *unreal.ProjectInfo) error {
-	if !cmd.Fast {
-		ver, err := projectInfo.GetEngineVersion()
+func (cmd *GenClangCmd) checkArgs() error {
+	if len(cmd.Generator) == 0 {
+		cmd.Generator = generatorAuto
+	}
+
+	if !core.StrContains(availableGenerators, cmd.Generator) {
+		return core.IllegalArgErrorf("Generator", "illegal value, must be oneof: %s",
+			strings.Join(availableGenerators, ", "))
+	}
+
+	if len(cmd.Platform) == 0 {
+		cmd.Platform = unreal.HostPlatform()
+	}
+
+	if !infocmd.IsLegalPlatform(cmd.Platform) {
+		return core.IllegalArgErrorf("Platform", "illegal value, must be oneof: %s",
+			infocmd.GetFmtAvailablePlatforms(", "))
+	}
+
+	if len(cmd.Configuration) == 0 {
+		cmd.Configuration = "Development"
+	}
+
+	if !infocmd.IsLegalConfiguration(cmd.Configuration) {
+		return core.IllegalArgErrorf("Configuration", "illegal value, must be oneof: %s",
+			infocmd.GetFmtAvailableConfigurations(", "))
+	}
+
+	filter, err := newEntryFilter(cmd.Include, cmd.Exclude)
+	if err != nil {
+		return err
+	}
+
+	cmd.filter = filter
+	return nil
+}
+
+// chooseGenerator 根据参数和引擎版本确定使用的生成方式。
+func (cmd *GenClangCmd) chooseGenerator(info *unreal.EngineInfo) string {
+	if cmd.Generator != generatorAuto {
+		return cmd.Generator
+	}
+
+	if unreal.SupportsClangDatabaseMode(info.BuildVersion) {
+		return generatorClangDb
+	}
+
+	return generatorVscode
+}
+
+// fastSrcDbPath 获取 --fast 模式下使用的已有 clang database 的路径。
+func (cmd *GenClangCmd) fastSrcDbPath(projectInfo *unreal.ProjectInfo) string {
+	switch cmd.Generator {
+	case generatorClangDb:
+		return projectInfo.ProjectGeneratedClangDbPath()
+	case generatorVscode:
+		return projectInfo.ProjectClangDbPath()
+	}
+
+	if _, err := os.Stat(projectInfo.ProjectGeneratedClangDbPath()); err == nil {
+		return projectInfo.ProjectGeneratedClangDbPath()
+	}
+
+	return projectInfo.ProjectClangDbPath()
+}
+
+// generateSrcDb 调用 UBT 生成原始的 clang database，返回生成的文件路径。
+func (cmd *GenClangCmd) generateSrcDb(projectInfo *unreal.ProjectInfo) (string, error) {
+	info, err := unreal.FindProjectEngineInfo(projectInfo)
+	if err != nil {
+		return "", fmt.Errorf("find Unreal engine info: %w", err)
+	}
+
+	core.LogD("find Unreal engine info: %s %s", info.RealVersion(), info.InstallPath)
+
+	generator := cmd.chooseGenerator(info)
+	inputHash, err := hashBuildInputs(projectInfo, info, generator, cmd.Target, cmd.Platform, cmd.Configuration)
+	if err != nil {
+		return "", err
+	}
+
+	if !cmd.Force {
+		cache, err := loadClangDbCache(projectInfo)
 		if err != nil {
-			return fmt.Errorf("get Unreal engine version: %w", err)
+			core.LogE("load clang database cache: %s", err.Error())
+		} else if cache.isValid(inputHash) {
+			core.LogI("build inputs not changed, skip UBT and use %s, use --force to regenerate", cache.SrcDbPath)
+			return cache.SrcDbPath, nil
 		}
+	}
+
+	core.LogD("generate clang database with %s generator", generator)
+	srcDbPath, err := cmd.executeGenerator(generator, info, projectInfo)
+	if err != nil {
+		return "", err
+	}
+
+	if err := saveClangDbCache(projectInfo, &clangDbCache{InputHash: inputHash, SrcDbPath: srcDbPath}); err != nil {
+		core.LogE("save clang database cache: %s", err.Error())
+	}
 
-		core.LogD("get Unreal engine version: %s", ver)
+	return srcDbPath, nil
+}
 
-		info, err := unreal.FindEngineInfo(ver)
+// executeGenerator 使用指定的生成方式调用 UBT，返回生成的原始 clang database 的路径。
+func (cmd *GenClangCmd) executeGenerator(generator string, info *unreal.EngineInfo, projectInfo *unreal.ProjectInfo) (string, error) {
+	if generator == generatorClangDb {
+		target, err := projectInfo.ChooseTarget(cmd.Target)
 		if err != nil {
-			return fmt.Errorf("find Unreal engine info: %w", err)
+			return "", err
 		}
 
-		core.LogD("find Unreal engine info: %s %s", info.Version, info.InstallPath)
+		return unreal.ExecuteUbtGenClangDatabase(info, projectInfo, target, cmd.Platform, cmd.Configuration)
+	}
+
+	if err := unreal.ExecuteUbtGenProject(info.InstallPath, projectInfo); err != nil {
+		return "", err
+	}
 
-		err = unreal.ExecuteUbtGenProject(info.InstallPath, projectInfo)
+	return projectInfo.ProjectClangDbPath(), nil
+}
+
+func (cmd *GenClangCmd) refreshClang(projectInfo *unreal.ProjectInfo) error {
+	var srcDbFilePath string
+	if cmd.Fast {
+		srcDbFilePath = cmd.fastSrcDbPath(projectInfo)
+	} else {
+		var err error
+		srcDbFilePath, err = cmd.generateSrcDb(projectInfo)
 		if err != nil {
 			return fmt.Errorf("generate clang database: %w", err)
 		}
@@ -42,15 +177,37 @@ func (cmd *GenClangCmd) refreshClang(projectInfo *unreal.ProjectInfo) error {
 		core.LogD("generate clang database success")
 	}
 
-	clangdFile, err := unreal.GenerateClangdFlagsFile(projectInfo.ProjectDir())
+	config, err := projectInfo.LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	if len(config.ClangFlagStyle) != 0 && !core.StrContains(availableFlagStyles, config.ClangFlagStyle) {
+		return core.IllegalArgErrorf("ClangFlagStyle", "illegal value in %s, must be oneof: %s",
+			projectInfo.ProjectConfigPath(), strings.Join(availableFlagStyles, ", "))
+	}
+
+	flagStyle := chooseFlagStyle(config.ClangFlagStyle, cmd.Platform)
+	core.LogD("translate flags in %s style", flagStyle)
+	translator := newFlagTranslator(flagStyle, config.ClangFlagRules)
+
+	clangdFlags, err := unreal.LoadClangdFlags()
+	if err != nil {
+		return err
+	}
+
+	// clangd_args 模板中是 MSVC 风格的参数
+	clangdFile, err := unreal.GenerateClangdFlagsFile(projectInfo.ProjectDir(), translator.translate(clangdFlags, "", true))
 	if err != nil {
 		return fmt.Errorf("generate clangd_args file: %w", err)
 	}
 
 	core.LogD("generate clangd_args file %s success", clangdFile)
 
-	srcDbFilePath := projectInfo.ProjectClangDbPath()
 	srcDbDataRaw, err := os.ReadFile(srcDbFilePath)
+	if err != nil {
+		return fmt.Errorf("read src clang database: %w", err)
+	}
 
 	// 使用 orderedmap.OrderedMap 以保持字段原有的顺序
 	var dbDataArray []orderedmap.OrderedMap
@@ -59,32 +216,151 @@ func (cmd *GenClangCmd) refreshClang(projectInfo *unreal.ProjectInfo) error {
 	}
 
 	clangdExtraArgs := fmt.Sprintf("@%s", clangdFile)
-	for _, elem := range dbDataArray {
-		args, ok := elem.Get("arguments")
-		if !ok {
-			continue
+	expander := newRspExpander(flagStyle)
+	for i := range dbDataArray {
+		elem := &dbDataArray[i]
+		if cmd.ExpandRsp {
+			expander.expandEntry(elem)
 		}
 
-		args = append(args.([]interface{}), clangdExtraArgs)
-		elem.Set("arguments", args)
+		translateEntry(elem, translator, expander.split, clangdExtraArgs)
+	}
+
+	dbDataArray, err = cmd.postProcessClangDb(projectInfo, dbDataArray, flagStyle)
+	if err != nil {
+		return err
 	}
 
 	dstDbFilePath := filepath.Join(projectInfo.ProjectDir(), "compile_commands.json")
-	dstDbDataRaw, err := json.MarshalIndent(dbDataArray, "", "\t")
+	if err := writeClangDb(dstDbFilePath, dbDataArray); err != nil {
+		return err
+	}
+
+	core.LogD("generate clang database from %s to %s success", srcDbFilePath, dstDbFilePath)
+	return nil
+}
+
+// postProcessClangDb 根据 --include 和 --exclude 过滤 clang database，指定了 --headers 时补充头文件的项，
+// 如果指定了 --split-by-module，还会将工程中每个 module 的项写入 module 目录，返回处理后的所有项。
+func (cmd *GenClangCmd) postProcessClangDb(projectInfo *unreal.ProjectInfo, dbDataArray []orderedmap.OrderedMap, flagStyle string) ([]orderedmap.OrderedMap, error) {
+	if cmd.filter.empty() && !cmd.SplitByModule && !cmd.Headers {
+		return dbDataArray, nil
+	}
+
+	projectDir := projectInfo.ProjectDir()
+	locator := unreal.NewModuleLocator()
+	var entries []orderedmap.OrderedMap
+	var infos []*entryInfo
+	for i := range dbDataArray {
+		info := newEntryInfo(&dbDataArray[i], projectDir, locator)
+		if !cmd.filter.match(info) {
+			core.LogD("exclude %s", info.file)
+			continue
+		}
+
+		entries = append(entries, dbDataArray[i])
+		infos = append(infos, info)
+	}
+
+	core.LogD("keep %d of %d entries", len(entries), len(dbDataArray))
+	if cmd.Headers {
+		synthesizer := &headerSynthesizer{
+			projectDir: projectDir,
+			flagStyle:  flagStyle,
+			filter:     cmd.filter,
+			locator:    locator,
+		}
+
+		count := len(entries)
+		entries, infos = synthesizer.synthesize(entries, infos)
+		core.LogD("add %d header entries", len(entries)-count)
+	}
+
+	if cmd.SplitByModule {
+		if err := writeModuleClangDbs(entries, infos); err != nil {
+			return nil, err
+		}
+	}
+
+	return entries, nil
+}
+
+// writeModuleClangDbs 将工程中每个 module 的项写入 module 目录下的 compile_commands.json。
+func writeModuleClangDbs(entries []orderedmap.OrderedMap, infos []*entryInfo) error {
+	var moduleDirs []string
+	moduleEntries := map[string][]orderedmap.OrderedMap{}
+	for i, info := range infos {
+		if len(info.moduleDir) == 0 || !info.inProject {
+			// 不在工程目录下的 module（如引擎的 module）不写入单独的 clang database
+			continue
+		}
+
+		if _, ok := moduleEntries[info.moduleDir]; !ok {
+			moduleDirs = append(moduleDirs, info.moduleDir)
+		}
+
+		moduleEntries[info.moduleDir] = append(moduleEntries[info.moduleDir], entries[i])
+	}
+
+	for _, dir := range moduleDirs {
+		dbPath := filepath.Join(dir, "compile_commands.json")
+		if err := writeClangDb(dbPath, moduleEntries[dir]); err != nil {
+			return err
+		}
+
+		core.LogI("write %d entries to %s", len(moduleEntries[dir]), dbPath)
+	}
+
+	return nil
+}
+
+// writeClangDb 将 clang database 写入文件。
+func writeClangDb(dbPath string, dbDataArray []orderedmap.OrderedMap) error {
+	if dbDataArray == nil {
+		dbDataArray = []orderedmap.OrderedMap{}
+	}
+
+	dbDataRaw, err := json.MarshalIndent(dbDataArray, "", "\t")
 	if err != nil {
 		return fmt.Errorf("marshal dst clang database: %w", err)
 	}
 
-	if err := os.WriteFile(dstDbFilePath, dstDbDataRaw, 0644); err != nil {
-		return fmt.Errorf("write dst clang database to %s: %w", dstDbFilePath, err)
+	if err := os.WriteFile(dbPath, dbDataRaw, 0644); err != nil {
+		return fmt.Errorf("write dst clang database to %s: %w", dbPath, err)
 	}
 
-	core.LogD("generate clang database from %s to %s success", srcDbFilePath, dstDbFilePath)
 	return nil
 }
 
+// translateArguments 转换 compile_commands.json 中一项的参数，第一个参数是编译器，不做转换，
+// 编译器不是 cl 或者 clang-cl 时参数不是 MSVC 风格的，不会使用以 / 开头的规则。
+func translateArguments(translator *flagTranslator, args []string, file string) []string {
+	if len(args) == 0 {
+		return args
+	}
+
+	return append([]string{args[0]}, translator.translate(args[1:], file, isMsvcCompiler(args[0]))...)
+}
+
+// translateEntry 转换 compile_commands.json 中一项的编译参数并在末尾加上 extraArgs。
+// GenerateClangDatabase 模式生成的是 command 字段，按照 split 拆分后同样需要转换，并写回为 arguments 字段。
+func translateEntry(entry *orderedmap.OrderedMap, translator *flagTranslator, split func(string) []string, extraArgs ...string) {
+	args, ok := entryArguments(entry, split)
+	if !ok {
+		return
+	}
+
+	file, _ := entry.Get("file")
+	fileStr, _ := file.(string)
+	setEntryArguments(entry, append(translateArguments(translator, args, fileStr), extraArgs...))
+}
+
 // Run 执行生成操作。
 func (cmd *GenClangCmd) Run() error {
+	if err := cmd.checkArgs(); err != nil {
+		return err
+	}
+
 	return osutil.DoInProjectRoot(cmd.ProjectFile, func(projPath string) error {
 		return cmd.refreshClang(&unreal.ProjectInfo{ProjectFilePath: projPath})
 	})
@@ -0,0 +1,268 @@
+package gencmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/iancoleman/orderedmap"
+	"github.com/zhiruili/urem/core"
+	"golang.org/x/text/encoding/unicode"
+)
+
+// maxRspDepth 是展开嵌套的 response file 时允许的最大深度。
+const maxRspDepth = 16
+
+// splitGnuArgs 按照 GNU 的规则拆分命令行，参考 clang 的实现：
+// llvm::cl::TokenizeGNUCommandLine
+func splitGnuArgs(s string) []string {
+	var args []string
+	var token strings.Builder
+	inToken := false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			if inToken {
+				args = append(args, token.String())
+				token.Reset()
+				inToken = false
+			}
+		case c == '\\':
+			inToken = true
+			if i+1 < len(s) {
+				i++
+				token.WriteByte(s[i])
+			}
+		case c == '\'' || c == '"':
+			inToken = true
+			quote := c
+			for i++; i < len(s) && s[i] != quote; i++ {
+				// 双引号中的反斜杠转义下一个字符，单引号中的反斜杠没有特殊含义
+				if quote == '"' && s[i] == '\\' && i+1 < len(s) {
+					i++
+				}
+				token.WriteByte(s[i])
+			}
+		default:
+			inToken = true
+			token.WriteByte(c)
+		}
+	}
+
+	if inToken {
+		args = append(args, token.String())
+	}
+
+	return args
+}
+
+// splitMsvcArgs 按照 MSVC 的规则拆分命令行，反斜杠只在双引号前有转义作用，参考 clang 的实现：
+// llvm::cl::TokenizeWindowsCommandLine
+func splitMsvcArgs(s string) []string {
+	var args []string
+	var token strings.Builder
+	inToken := false
+	quoted := false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case !quoted && (c == ' ' || c == '\t' || c == '\r' || c == '\n'):
+			if inToken {
+				args = append(args, token.String())
+				token.Reset()
+				inToken = false
+			}
+		case c == '\\':
+			inToken = true
+			n := 0
+			for ; i < len(s) && s[i] == '\\'; i++ {
+				n++
+			}
+
+			if i < len(s) && s[i] == '"' {
+				// 2n 个反斜杠加引号得到 n 个反斜杠，引号继续按照引号处理；2n+1 个则得到 n 个反斜杠和一个引号
+				token.WriteString(strings.Repeat("\\", n/2))
+				if n%2 == 1 {
+					token.WriteByte('"')
+					continue
+				}
+			} else {
+				token.WriteString(strings.Repeat("\\", n))
+			}
+
+			i--
+		case c == '"':
+			inToken = true
+			if quoted && i+1 < len(s) && s[i+1] == '"' {
+				token.WriteByte('"')
+				i++
+			} else {
+				quoted = !quoted
+			}
+		default:
+			inToken = true
+			token.WriteByte(c)
+		}
+	}
+
+	if inToken {
+		args = append(args, token.String())
+	}
+
+	return args
+}
+
+// argsSplitter 获取参数风格对应的命令行拆分函数。
+func argsSplitter(style string) func(string) []string {
+	if style == flagStyleMsvc {
+		return splitMsvcArgs
+	}
+
+	return splitGnuArgs
+}
+
+// readRspFile 读取 response file 的内容，支持带 BOM 的 UTF-8 和 UTF-16 编码。
+func readRspFile(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	if bytes.HasPrefix(content, []byte{0xFF, 0xFE}) || bytes.HasPrefix(content, []byte{0xFE, 0xFF}) {
+		decoded, err := unicode.UTF16(unicode.LittleEndian, unicode.ExpectBOM).NewDecoder().Bytes(content)
+		if err != nil {
+			return "", fmt.Errorf("decode %s: %w", path, err)
+		}
+
+		return string(decoded), nil
+	}
+
+	return string(bytes.TrimPrefix(content, []byte{0xEF, 0xBB, 0xBF})), nil
+}
+
+// rspExpander 负责将参数中的 @file 展开为 response file 中的参数。
+type rspExpander struct {
+	split   func(string) []string
+	missing map[string]bool // 已经报告过的找不到的 response file
+}
+
+func newRspExpander(style string) *rspExpander {
+	return &rspExpander{split: argsSplitter(style), missing: map[string]bool{}}
+}
+
+// expand 展开 args 中的 response file，相对路径基于 dir，找不到的文件会输出警告并保留原参数。
+func (e *rspExpander) expand(args []string, dir string) []string {
+	return e.expandWithDepth(args, dir, 0)
+}
+
+func (e *rspExpander) expandWithDepth(args []string, dir string, depth int) []string {
+	result := make([]string, 0, len(args))
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "@") || len(arg) == 1 {
+			result = append(result, arg)
+			continue
+		}
+
+		rspPath := arg[1:]
+		if !filepath.IsAbs(rspPath) {
+			rspPath = filepath.Join(dir, rspPath)
+		}
+
+		if depth >= maxRspDepth {
+			core.LogE("warning: response file %s nested too deep, keep it unexpanded", rspPath)
+			result = append(result, arg)
+			continue
+		}
+
+		content, err := readRspFile(rspPath)
+		if err != nil {
+			if !e.missing[rspPath] {
+				e.missing[rspPath] = true
+				core.LogE("warning: read response file: %s", err.Error())
+			}
+
+			result = append(result, arg)
+			continue
+		}
+
+		result = append(result, e.expandWithDepth(e.split(content), dir, depth+1)...)
+	}
+
+	return result
+}
+
+// entryArguments 获取 compile_commands.json 中一项的参数，使用 command 字段的项按照 split 拆分，
+// 两者都没有或者类型不对（如 arguments 是字符串或 null）时返回 false。
+func entryArguments(entry *orderedmap.OrderedMap, split func(string) []string) ([]string, bool) {
+	if value, ok := entry.Get("arguments"); ok {
+		values, ok := value.([]interface{})
+		if !ok {
+			return nil, false
+		}
+
+		var args []string
+		for _, arg := range values {
+			if s, ok := arg.(string); ok {
+				args = append(args, s)
+			}
+		}
+
+		return args, true
+	}
+
+	if value, ok := entry.Get("command"); ok {
+		command, ok := value.(string)
+		if !ok {
+			return nil, false
+		}
+
+		return split(command), true
+	}
+
+	return nil, false
+}
+
+// setEntryArguments 设置一项的 arguments 字段，使用 command 字段的项会被替换为同一位置的 arguments 字段。
+func setEntryArguments(entry *orderedmap.OrderedMap, args []string) {
+	values := make([]interface{}, 0, len(args))
+	for _, arg := range args {
+		values = append(values, arg)
+	}
+
+	if _, ok := entry.Get("command"); !ok {
+		entry.Set("arguments", values)
+		return
+	}
+
+	pos := 0
+	for i, key := range entry.Keys() {
+		if key == "command" {
+			pos = i
+			break
+		}
+	}
+
+	entry.Delete("command")
+	entry.Set("arguments", values)
+	entry.SortKeys(func(keys []string) {
+		// 将新加入的 arguments 移动到原来 command 的位置
+		copy(keys[pos+1:], keys[pos:len(keys)-1])
+		keys[pos] = "arguments"
+	})
+}
+
+// expandEntry 展开 compile_commands.json 中一项的 response file，
+// 使用 command 字段的项会被拆分并替换为同一位置的 arguments 字段。
+func (e *rspExpander) expandEntry(entry *orderedmap.OrderedMap) {
+	dir := ""
+	if value, ok := entry.Get("directory"); ok {
+		dir, _ = value.(string)
+	}
+
+	if args, ok := entryArguments(entry, e.split); ok {
+		setEntryArguments(entry, e.expand(args, dir))
+	}
+}
@@ -0,0 +1,126 @@
+package gencmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/iancoleman/orderedmap"
+)
+
+// TestSplitArgs 测试 splitGnuArgs 和 splitMsvcArgs 函数。
+func TestSplitArgs(t *testing.T) {
+	cases := []struct {
+		name   string
+		style  string
+		line   string
+		expect []string
+	}{
+		{name: "gnu simple", style: flagStyleGnu, line: "-c  -DA=1\n-I/a", expect: []string{"-c", "-DA=1", "-I/a"}},
+		{name: "gnu quotes", style: flagStyleGnu, line: `"-I/a b" '-DB="x"' -DC=\"y\"`, expect: []string{"-I/a b", `-DB="x"`, `-DC="y"`}},
+		{name: "gnu escape in double quotes", style: flagStyleGnu, line: `"a\"b\\c"`, expect: []string{`a"b\c`}},
+		{name: "msvc path", style: flagStyleMsvc, line: `/I "C:\Program Files\UE" /c C:\a\b.cpp`, expect: []string{"/I", `C:\Program Files\UE`, "/c", `C:\a\b.cpp`}},
+		{name: "msvc backslash quote", style: flagStyleMsvc, line: `"C:\dir\\" /DA=\"x\" a\\\"b`, expect: []string{`C:\dir\`, `/DA="x"`, `a\"b`}},
+		{name: "msvc double quote in quotes", style: flagStyleMsvc, line: `"a""b" c`, expect: []string{`a"b`, "c"}},
+		{name: "empty", style: flagStyleGnu, line: " \r\n", expect: nil},
+	}
+
+	for i, c := range cases {
+		actual := argsSplitter(c.style)(c.line)
+		if !reflect.DeepEqual(actual, c.expect) {
+			t.Errorf("%d:%s: expect %q, actual %q", i, c.name, c.expect, actual)
+		}
+	}
+}
+
+// TestRspExpander 测试 rspExpander 展开 compile_commands.json 中的项。
+func TestRspExpander(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "A.rsp"), []byte("-DA=1\n\"-I/a b\"\n@B.rsp\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "B.rsp"), []byte("\xEF\xBB\xBF-DB=2"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name   string
+		entry  string
+		expect string
+	}{
+		{
+			name:   "arguments",
+			entry:  `{"file":"a.cpp","arguments":["clang++","@A.rsp","a.cpp"],"directory":"` + dir + `"}`,
+			expect: `{"file":"a.cpp","arguments":["clang++","-DA=1","-I/a b","-DB=2","a.cpp"],"directory":"` + dir + `"}`,
+		},
+		{
+			name:   "command",
+			entry:  `{"file":"a.cpp","command":"clang++ @` + filepath.Join(dir, "B.rsp") + ` a.cpp","directory":"/"}`,
+			expect: `{"file":"a.cpp","arguments":["clang++","-DB=2","a.cpp"],"directory":"/"}`,
+		},
+		{
+			name:   "missing rsp",
+			entry:  `{"file":"a.cpp","arguments":["clang++","@Missing.rsp"],"directory":"` + dir + `"}`,
+			expect: `{"file":"a.cpp","arguments":["clang++","@Missing.rsp"],"directory":"` + dir + `"}`,
+		},
+		{
+			name:   "string arguments",
+			entry:  `{"file":"a.cpp","arguments":"clang++ @A.rsp","directory":"` + dir + `"}`,
+			expect: `{"file":"a.cpp","arguments":"clang++ @A.rsp","directory":"` + dir + `"}`,
+		},
+		{
+			name:   "null arguments",
+			entry:  `{"file":"a.cpp","arguments":null,"directory":"` + dir + `"}`,
+			expect: `{"file":"a.cpp","arguments":null,"directory":"` + dir + `"}`,
+		},
+	}
+
+	for i, c := range cases {
+		var entry orderedmap.OrderedMap
+		if err := json.Unmarshal([]byte(c.entry), &entry); err != nil {
+			t.Fatalf("%d:%s: unmarshal entry: %s", i, c.name, err)
+		}
+
+		newRspExpander(flagStyleGnu).expandEntry(&entry)
+		actual, err := json.Marshal(entry)
+		if err != nil {
+			t.Fatalf("%d:%s: marshal entry: %s", i, c.name, err)
+		}
+
+		if string(actual) != c.expect {
+			t.Errorf("%d:%s: expect %s, actual %s", i, c.name, c.expect, actual)
+		}
+	}
+}
+
+// TestEntryArguments 测试获取 compile_commands.json 中一项的参数。
+func TestEntryArguments(t *testing.T) {
+	cases := []struct {
+		name     string
+		entry    string
+		expectOK bool
+		expect   []string
+	}{
+		{name: "arguments", entry: `{"arguments":["clang++","-c","a.cpp"]}`, expectOK: true, expect: []string{"clang++", "-c", "a.cpp"}},
+		{name: "command", entry: `{"command":"clang++ -c a.cpp"}`, expectOK: true, expect: []string{"clang++", "-c", "a.cpp"}},
+		{name: "missing", entry: `{"file":"a.cpp"}`, expectOK: false},
+		{name: "string arguments", entry: `{"arguments":"clang++ -c a.cpp"}`, expectOK: false},
+		{name: "null arguments", entry: `{"arguments":null}`, expectOK: false},
+		{name: "array command", entry: `{"command":["clang++"]}`, expectOK: false},
+	}
+
+	for i, c := range cases {
+		var entry orderedmap.OrderedMap
+		if err := json.Unmarshal([]byte(c.entry), &entry); err != nil {
+			t.Fatalf("%d:%s: unmarshal entry: %s", i, c.name, err)
+		}
+
+		actual, ok := entryArguments(&entry, argsSplitter(flagStyleGnu))
+		if ok != c.expectOK || !reflect.DeepEqual(actual, c.expect) {
+			t.Errorf("%d:%s: expect (%q, %t), actual (%q, %t)", i, c.name, c.expect, c.expectOK, actual, ok)
+		}
+	}
+}
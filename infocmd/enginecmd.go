@@ -14,22 +14,31 @@ type InfoEngineCmd struct {
 
 func printEngineInfo(projectFilePath string) error {
 	projectInfo := &unreal.ProjectInfo{ProjectFilePath: projectFilePath}
-	ver, err := projectInfo.GetEngineVersion()
+	association, err := projectInfo.GetEngineAssociation()
 	if err != nil {
-		return fmt.Errorf("get Unreal engine version: %s", err.Error())
+		return fmt.Errorf("get Unreal engine association: %s", err.Error())
 	}
 
-	fmt.Printf("Unreal Version: %s\n", ver)
+	fmt.Printf("Engine Association: %s\n", association)
 
-	info, err := unreal.FindEngineInfo(ver)
+	info, err := unreal.FindProjectEngineInfo(projectInfo)
 	if err != nil {
 		return fmt.Errorf("find Unreal engine info: %w", err)
 	}
 
-	fmt.Printf("Install Path: %s\n", info.InstallPath)
+	fmt.Printf("Engine Source: %s\n", info.Source)
+	printEngineInfoDetail(info)
 	return nil
 }
 
+func printEngineInfoDetail(info *unreal.EngineInfo) {
+	fmt.Printf("Unreal Version: %s\n", info.RealVersion())
+	if info.BuildVersion != nil && len(info.Version) != 0 && info.Version != info.RealVersion() {
+		fmt.Printf("Identifier: %s\n", info.Version)
+	}
+	fmt.Printf("Install Path: %s\n", info.InstallPath)
+}
+
 func printAllEngineInfos() error {
 	infos, err := unreal.FindAllEngineInfos()
 	if err != nil {
@@ -41,8 +50,7 @@ func printAllEngineInfos() error {
 			fmt.Println("")
 		}
 
-		fmt.Printf("Unreal Version: %s\n", info.Version)
-		fmt.Printf("Install Path: %s\n", info.InstallPath)
+		printEngineInfoDetail(info)
 	}
 
 	return nil
@@ -68,9 +68,59 @@ func IsLegalLoadingPhase(t string) bool {
 	return core.StrContains(availableLoadingPhases, t)
 }
 
+// https://docs.unrealengine.com/4.26/en-US/API/Runtime/Core/Misc/EBuildConfiguration/
+var availableConfigurations = []string{
+	"Debug",
+	"DebugGame",
+	"Development",
+	"Shipping",
+	"Test",
+}
+
+// GetAvailableConfigurations 获取所有合法的 build configuration 类型。
+func GetAvailableConfigurations() []string {
+	return availableConfigurations
+}
+
+// GetFmtAvailableConfigurations 获取所有合法的 build configuration 类型的格式化字符串。
+func GetFmtAvailableConfigurations(sep string) string {
+	return strings.Join(availableConfigurations, sep)
+}
+
+// IsLegalConfiguration 检查一个字符串是否是一个合法的 build configuration 类型。
+func IsLegalConfiguration(t string) bool {
+	return core.StrContains(availableConfigurations, t)
+}
+
+// 常用的平台，参考 UBT 中的 UnrealTargetPlatform
+var availablePlatforms = []string{
+	"Win64",
+	"Mac",
+	"Linux",
+	"LinuxArm64",
+	"Android",
+	"IOS",
+	"TVOS",
+}
+
+// GetAvailablePlatforms 获取所有合法的 target platform 类型。
+func GetAvailablePlatforms() []string {
+	return availablePlatforms
+}
+
+// GetFmtAvailablePlatforms 获取所有合法的 target platform 类型的格式化字符串。
+func GetFmtAvailablePlatforms(sep string) string {
+	return strings.Join(availablePlatforms, sep)
+}
+
+// IsLegalPlatform 检查一个字符串是否是一个合法的 target platform 类型。
+func IsLegalPlatform(t string) bool {
+	return core.StrContains(availablePlatforms, t)
+}
+
 // InfoEnumCmd 实现了 enum 查询子命令。
 type InfoEnumCmd struct {
-	Target string `arg:"positional,required" help:"list target modtype/loadphase"`
+	Target string `arg:"positional,required" help:"list target modtype/loadphase/config/platform"`
 }
 
 // Run 执行 info enum 子命令。
@@ -80,8 +130,12 @@ func (cmd *InfoEnumCmd) Run() error {
 		fmt.Println(GetFmtAvailableModuleTypes("\n"))
 	case "loadphase":
 		fmt.Println(GetFmtAvailableLoadingPhases("\n"))
+	case "config":
+		fmt.Println(GetFmtAvailableConfigurations("\n"))
+	case "platform":
+		fmt.Println(GetFmtAvailablePlatforms("\n"))
 	default:
-		return fmt.Errorf("missing target: modtype/loadphase")
+		return fmt.Errorf("missing target: modtype/loadphase/config/platform")
 	}
 
 	return nil
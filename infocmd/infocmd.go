@@ -6,8 +6,10 @@ import (
 
 // Cmd 是 info 子命令的集合。
 type Cmd struct {
-	EngineCommand *InfoEngineCmd `arg:"subcommand:ue" help:"print associated engine info of the prject."`
-	EnumCommand   *InfoEnumCmd   `arg:"subcommand:enum" help:"print available enum value."`
+	EngineCommand  *InfoEngineCmd  `arg:"subcommand:ue" help:"print associated engine info of the prject."`
+	EnumCommand    *InfoEnumCmd    `arg:"subcommand:enum" help:"print available enum value."`
+	PluginsCommand *InfoPluginsCmd `arg:"subcommand:plugins" help:"print marketplace plugins installed in engines."`
+	ProjectCommand *InfoProjectCmd `arg:"subcommand:project" help:"print modules, plugins, targets and engine of the project."`
 }
 
 // Run 实现了 subCmd 的接口。
@@ -16,6 +18,10 @@ func (cmd *Cmd) Run() error {
 		return cmd.EngineCommand.Run()
 	} else if cmd.EnumCommand != nil {
 		return cmd.EnumCommand.Run()
+	} else if cmd.PluginsCommand != nil {
+		return cmd.PluginsCommand.Run()
+	} else if cmd.ProjectCommand != nil {
+		return cmd.ProjectCommand.Run()
 	}
 
 	return fmt.Errorf("missing subcommand of info cmd")
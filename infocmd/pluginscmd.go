@@ -0,0 +1,50 @@
+package infocmd
+
+import (
+	"fmt"
+
+	"github.com/zhiruili/urem/unreal"
+)
+
+// InfoPluginsCmd 是用于列出通过 launcher 安装到引擎中的 Marketplace 插件的子命令。
+type InfoPluginsCmd struct {
+	LauncherFile string `arg:"--launcher-file" help:"path of LauncherInstalled.dat, use the platform default path if not set"`
+	Engine       string `arg:"positional" help:"only print plugins installed in the engine with given version or identifier"`
+}
+
+// Run 执行插件信息查找逻辑。
+func (cmd *InfoPluginsCmd) Run() error {
+	if len(cmd.LauncherFile) != 0 {
+		unreal.LauncherInstalledPath = cmd.LauncherFile
+	}
+
+	plugins, err := unreal.FindAllPluginInstallInfos()
+	if err != nil {
+		return fmt.Errorf("find installed plugins: %w", err)
+	}
+
+	var lastEngine *unreal.EngineInfo
+	for _, plugin := range plugins {
+		if len(cmd.Engine) != 0 && plugin.Engine.Version != cmd.Engine && plugin.Engine.RealVersion() != cmd.Engine {
+			continue
+		}
+
+		if plugin.Engine != lastEngine {
+			if lastEngine != nil {
+				fmt.Println("")
+			}
+
+			fmt.Printf("Unreal Version: %s\n", plugin.Engine.RealVersion())
+			fmt.Printf("Install Path: %s\n", plugin.Engine.InstallPath)
+			lastEngine = plugin.Engine
+		}
+
+		fmt.Printf("  %s (%s): %s\n", plugin.Name, plugin.AppVersion, plugin.InstallPath)
+	}
+
+	if lastEngine == nil {
+		fmt.Println("no installed plugin found")
+	}
+
+	return nil
+}
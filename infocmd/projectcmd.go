@@ -0,0 +1,366 @@
+package infocmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/zhiruili/urem/core"
+	"github.com/zhiruili/urem/descriptor"
+	"github.com/zhiruili/urem/osutil"
+	"github.com/zhiruili/urem/unreal"
+)
+
+// 支持的输出格式。
+const (
+	formatText     = "text"
+	formatJSON     = "json"
+	formatMarkdown = "markdown"
+)
+
+var availableFormats = []string{formatText, formatJSON, formatMarkdown}
+
+// 插件所在的位置。
+const (
+	locationProject = "project"
+	locationEngine  = "engine"
+	locationMissing = "missing"
+	locationUnknown = "unknown" // 找不到引擎时无法确定工程中没有的插件是否存在
+)
+
+// InfoProjectCmd 是用于输出工程概况的子命令。
+type InfoProjectCmd struct {
+	Format      string `arg:"-f,--format" help:"output format text/json/markdown" default:"text"`
+	ProjectFile string `arg:"positional" help:"project file or a dir under the project" default:"."`
+}
+
+type engineSummary struct {
+	Version string `json:"version"`
+	Path    string `json:"path"`
+	Source  string `json:"source"`
+}
+
+type moduleSummary struct {
+	Name         string `json:"name"`
+	Type         string `json:"type"`
+	LoadingPhase string `json:"loadingPhase,omitempty"`
+	Plugin       string `json:"plugin,omitempty"` // 所属的工程插件，为空时属于工程
+	Dir          string `json:"dir,omitempty"`    // *.Build.cs 所在目录，找不到时为空
+	SourceFiles  int    `json:"sourceFiles"`
+}
+
+type pluginSummary struct {
+	Name       string `json:"name"`
+	Enabled    bool   `json:"enabled"`
+	Referenced bool   `json:"referenced"` // 是否在 .uproject 的 Plugins 中引用
+	Location   string `json:"location"`   // project/engine/missing/unknown
+	Path       string `json:"path,omitempty"`
+}
+
+type projectSummary struct {
+	Name              string           `json:"name"`
+	File              string           `json:"file"`
+	EngineAssociation string           `json:"engineAssociation"`
+	Engine            *engineSummary   `json:"engine,omitempty"` // 找不到引擎时为空
+	Targets           []string         `json:"targets"`
+	Modules           []*moduleSummary `json:"modules"`
+	Plugins           []*pluginSummary `json:"plugins"`
+	EnginePluginCount int              `json:"enginePluginCount"`
+
+	moduleDirs map[string]bool // 所有包含 *.Build.cs 的目录，包括没有在描述文件中声明的 module
+}
+
+// displayPath 将工程目录下的路径转换为相对路径，其他路径保持不变。
+func displayPath(projectDir string, p string) string {
+	rel, err := filepath.Rel(projectDir, p)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return p
+	}
+
+	return filepath.ToSlash(rel)
+}
+
+// addModules 将描述文件中声明的 module 加入 summary，module 的目录从 sourceDir 下的 *.Build.cs 中查找。
+func (s *projectSummary) addModules(modules []*descriptor.ModuleDescriptor, plugin string, sourceDir string) error {
+	moduleFiles, err := unreal.FindModuleFiles(sourceDir)
+	if err != nil {
+		return fmt.Errorf("find modules in %s: %w", sourceDir, err)
+	}
+
+	moduleDirs := map[string]string{}
+	for _, file := range moduleFiles {
+		moduleDirs[unreal.ModuleNameOfFile(file)] = filepath.Dir(file)
+		s.moduleDirs[filepath.Dir(file)] = true
+	}
+
+	for _, m := range modules {
+		s.Modules = append(s.Modules, &moduleSummary{
+			Name:         m.Name,
+			Type:         m.Type,
+			LoadingPhase: m.LoadingPhase,
+			Plugin:       plugin,
+			Dir:          moduleDirs[m.Name],
+		})
+	}
+
+	return nil
+}
+
+// countSourceFiles 统计每个 module 目录下源文件的数量，嵌套在 module 目录中的其他 module 不计入。
+func (s *projectSummary) countSourceFiles() error {
+	for _, m := range s.Modules {
+		if len(m.Dir) == 0 {
+			continue
+		}
+
+		err := filepath.WalkDir(m.Dir, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if d.IsDir() {
+				if p != m.Dir && (strings.HasPrefix(d.Name(), ".") || s.moduleDirs[p]) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+
+			if unreal.IsSourceFile(d.Name()) {
+				m.SourceFiles++
+			}
+
+			return nil
+		})
+
+		if err != nil {
+			return fmt.Errorf("count source files of module %s: %w", m.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// collectProjectSummary 收集工程的 module、插件和 target 信息，engine 为 nil 时不查找引擎中的插件。
+func collectProjectSummary(projectInfo *unreal.ProjectInfo, engine *unreal.EngineInfo) (*projectSummary, error) {
+	project, err := descriptor.LoadProject(projectInfo.ProjectFilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &projectSummary{
+		Name:              projectInfo.ProjectName(),
+		File:              projectInfo.ProjectFilePath,
+		EngineAssociation: project.EngineAssociation,
+		Targets:           []string{},
+		Modules:           []*moduleSummary{},
+		Plugins:           []*pluginSummary{},
+		moduleDirs:        map[string]bool{},
+	}
+
+	if targets, err := projectInfo.FindTargets(); err != nil {
+		return nil, err
+	} else if len(targets) != 0 {
+		s.Targets = targets
+	}
+
+	if err := s.addModules(project.Modules, "", projectInfo.ProjectSourceDir()); err != nil {
+		return nil, err
+	}
+
+	projectPluginFiles, err := projectInfo.FindProjectPluginFiles(project.AdditionalPluginDirectories)
+	if err != nil {
+		return nil, err
+	}
+
+	// 工程插件默认启用，除非插件中指定了 EnabledByDefault 为 false
+	present := map[string]*pluginSummary{}
+	for _, file := range projectPluginFiles {
+		name := unreal.PluginNameOfFile(file)
+		plugin, err := descriptor.LoadPlugin(file)
+		if err != nil {
+			core.LogE("warning: %s", err.Error())
+			continue
+		}
+
+		if err := s.addModules(plugin.Modules, name, filepath.Join(filepath.Dir(file), "Source")); err != nil {
+			return nil, err
+		}
+
+		present[name] = &pluginSummary{
+			Name:     name,
+			Enabled:  plugin.EnabledByDefault == nil || *plugin.EnabledByDefault,
+			Location: locationProject,
+			Path:     file,
+		}
+	}
+
+	if err := s.countSourceFiles(); err != nil {
+		return nil, err
+	}
+
+	if engine != nil {
+		s.Engine = &engineSummary{Version: engine.RealVersion(), Path: engine.InstallPath, Source: string(engine.Source)}
+		enginePluginFiles, err := unreal.FindPluginFiles(engine.EnginePluginsDir())
+		if err != nil {
+			return nil, fmt.Errorf("find engine plugins: %w", err)
+		}
+
+		s.EnginePluginCount = len(enginePluginFiles)
+		for _, file := range enginePluginFiles {
+			name := unreal.PluginNameOfFile(file)
+			if _, ok := present[name]; !ok {
+				present[name] = &pluginSummary{Name: name, Location: locationEngine, Path: file}
+			}
+		}
+	}
+
+	// 先按顺序输出引用的插件，再输出没有引用的工程插件
+	notFound := locationMissing
+	if engine == nil {
+		notFound = locationUnknown
+	}
+
+	for _, ref := range project.Plugins {
+		plugin := &pluginSummary{Name: ref.Name, Location: notFound}
+		if p, ok := present[ref.Name]; ok {
+			plugin.Location, plugin.Path = p.Location, p.Path
+			delete(present, ref.Name)
+		}
+
+		plugin.Enabled = ref.Enabled
+		plugin.Referenced = true
+		s.Plugins = append(s.Plugins, plugin)
+	}
+
+	var others []*pluginSummary
+	for _, p := range present {
+		if p.Location == locationProject {
+			others = append(others, p)
+		}
+	}
+
+	sort.Slice(others, func(i, j int) bool { return others[i].Name < others[j].Name })
+	s.Plugins = append(s.Plugins, others...)
+	return s, nil
+}
+
+func writeProjectText(w io.Writer, s *projectSummary) error {
+	fmt.Fprintf(w, "Project: %s\n", s.Name)
+	fmt.Fprintf(w, "Project File: %s\n", s.File)
+	fmt.Fprintf(w, "Engine Association: %s\n", s.EngineAssociation)
+	if s.Engine != nil {
+		fmt.Fprintf(w, "Unreal Version: %s\n", s.Engine.Version)
+		fmt.Fprintf(w, "Install Path: %s\n", s.Engine.Path)
+		fmt.Fprintf(w, "Engine Source: %s\n", s.Engine.Source)
+	}
+	fmt.Fprintf(w, "Targets: %s\n", strings.Join(s.Targets, ", "))
+
+	projectDir := filepath.Dir(s.File)
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "\nModules:\n")
+	fmt.Fprintln(tw, "  NAME\tTYPE\tLOADING PHASE\tPLUGIN\tSOURCE FILES\tDIR")
+	for _, m := range s.Modules {
+		fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\t%d\t%s\n", m.Name, m.Type, orDash(m.LoadingPhase), orDash(m.Plugin),
+			m.SourceFiles, orDash(displayPath(projectDir, m.Dir)))
+	}
+
+	fmt.Fprintf(tw, "\nPlugins:\n")
+	fmt.Fprintln(tw, "  NAME\tENABLED\tREFERENCED\tLOCATION\tPATH")
+	for _, p := range s.Plugins {
+		fmt.Fprintf(tw, "  %s\t%v\t%v\t%s\t%s\n", p.Name, p.Enabled, p.Referenced, p.Location, orDash(displayPath(projectDir, p.Path)))
+	}
+
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if s.Engine != nil {
+		fmt.Fprintf(w, "\nEngine Plugins: %d\n", s.EnginePluginCount)
+	}
+
+	return nil
+}
+
+func writeProjectMarkdown(w io.Writer, s *projectSummary) error {
+	fmt.Fprintf(w, "# %s\n\n", s.Name)
+	fmt.Fprintf(w, "- Project File: `%s`\n", s.File)
+	fmt.Fprintf(w, "- Engine Association: `%s`\n", s.EngineAssociation)
+	if s.Engine != nil {
+		fmt.Fprintf(w, "- Engine: %s `%s` (%s)\n", s.Engine.Version, s.Engine.Path, s.Engine.Source)
+		fmt.Fprintf(w, "- Engine Plugins: %d\n", s.EnginePluginCount)
+	}
+	fmt.Fprintf(w, "- Targets: %s\n", strings.Join(core.StrSliceMap(s.Targets, func(t string) string {
+		return "`" + t + "`"
+	}), ", "))
+
+	projectDir := filepath.Dir(s.File)
+	fmt.Fprintf(w, "\n## Modules\n\n")
+	fmt.Fprintln(w, "| Name | Type | Loading Phase | Plugin | Source Files | Dir |")
+	fmt.Fprintln(w, "| --- | --- | --- | --- | ---: | --- |")
+	for _, m := range s.Modules {
+		fmt.Fprintf(w, "| %s | %s | %s | %s | %d | %s |\n", m.Name, m.Type, orDash(m.LoadingPhase), orDash(m.Plugin),
+			m.SourceFiles, orDash(displayPath(projectDir, m.Dir)))
+	}
+
+	fmt.Fprintf(w, "\n## Plugins\n\n")
+	fmt.Fprintln(w, "| Name | Enabled | Referenced | Location | Path |")
+	fmt.Fprintln(w, "| --- | --- | --- | --- | --- |")
+	for _, p := range s.Plugins {
+		fmt.Fprintf(w, "| %s | %v | %v | %s | %s |\n", p.Name, p.Enabled, p.Referenced, p.Location, orDash(displayPath(projectDir, p.Path)))
+	}
+
+	return nil
+}
+
+func writeProjectJSON(w io.Writer, s *projectSummary) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(s)
+}
+
+func orDash(s string) string {
+	if len(s) == 0 {
+		return "-"
+	}
+
+	return s
+}
+
+func (cmd *InfoProjectCmd) printProjectInfo(projectFilePath string) error {
+	projectInfo := &unreal.ProjectInfo{ProjectFilePath: projectFilePath}
+	engine, err := unreal.FindProjectEngineInfo(projectInfo)
+	if err != nil {
+		core.LogE("warning: find Unreal engine info: %s", err.Error())
+		engine = nil
+	}
+
+	s, err := collectProjectSummary(projectInfo, engine)
+	if err != nil {
+		return err
+	}
+
+	switch cmd.Format {
+	case formatJSON:
+		return writeProjectJSON(os.Stdout, s)
+	case formatMarkdown:
+		return writeProjectMarkdown(os.Stdout, s)
+	default:
+		return writeProjectText(os.Stdout, s)
+	}
+}
+
+// Run 执行工程信息查找逻辑。
+func (cmd *InfoProjectCmd) Run() error {
+	if !core.StrContains(availableFormats, cmd.Format) {
+		return core.IllegalArgErrorf("Format", "illegal value, must be oneof: %s", strings.Join(availableFormats, ", "))
+	}
+
+	return osutil.DoInProjectRoot(cmd.ProjectFile, cmd.printProjectInfo)
+}
@@ -0,0 +1,96 @@
+package infocmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/zhiruili/urem/unreal"
+)
+
+// TestCollectProjectSummary 测试收集工程的 module、插件和 target 信息。
+func TestCollectProjectSummary(t *testing.T) {
+	projectDir := t.TempDir()
+	engineDir := t.TempDir()
+	writeFile := func(path string, content string) {
+		if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writeFile(filepath.Join(projectDir, "Game.uproject"), `{
+	"FileVersion": 3,
+	"Modules": [{"Name": "Game", "Type": "Runtime", "LoadingPhase": "Default"}],
+	"Plugins": [
+		{"Name": "Tools", "Enabled": false},
+		{"Name": "Paper2D", "Enabled": true},
+		{"Name": "Missing", "Enabled": true}
+	]
+}`)
+	writeFile(filepath.Join(projectDir, "Source/Game.Target.cs"), "")
+	writeFile(filepath.Join(projectDir, "Source/Game/Game.Build.cs"), "")
+	writeFile(filepath.Join(projectDir, "Source/Game/Private/Game.cpp"), "")
+	writeFile(filepath.Join(projectDir, "Source/Game/Public/Game.h"), "")
+	writeFile(filepath.Join(projectDir, "Source/Game/Private/Nested/Nested.Build.cs"), "")
+	writeFile(filepath.Join(projectDir, "Source/Game/Private/Nested/Nested.cpp"), "")
+	writeFile(filepath.Join(projectDir, "Plugins/Tools/Tools.uplugin"), `{
+	"FileVersion": 3,
+	"Modules": [{"Name": "ToolsEditor", "Type": "Editor"}]
+}`)
+	writeFile(filepath.Join(projectDir, "Plugins/Tools/Source/ToolsEditor/ToolsEditor.Build.cs"), "")
+	writeFile(filepath.Join(projectDir, "Plugins/Tools/Source/ToolsEditor/Private/A.cpp"), "")
+	writeFile(filepath.Join(projectDir, "Plugins/Hidden/Hidden.uplugin"), `{"FileVersion": 3, "EnabledByDefault": false}`)
+	writeFile(filepath.Join(engineDir, "Engine/Plugins/2D/Paper2D/Paper2D.uplugin"), "{}")
+
+	projectInfo := &unreal.ProjectInfo{ProjectFilePath: filepath.Join(projectDir, "Game.uproject")}
+	engine := &unreal.EngineInfo{Version: "5.3", InstallPath: engineDir}
+	s, err := collectProjectSummary(projectInfo, engine)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(s.Targets, []string{"Game"}) {
+		t.Errorf("expect targets [Game], actual %v", s.Targets)
+	}
+
+	expectModules := []moduleSummary{
+		{Name: "Game", Type: "Runtime", LoadingPhase: "Default", Dir: filepath.Join(projectDir, "Source/Game"), SourceFiles: 2},
+		{Name: "ToolsEditor", Type: "Editor", Plugin: "Tools", Dir: filepath.Join(projectDir, "Plugins/Tools/Source/ToolsEditor"), SourceFiles: 1},
+	}
+
+	if len(s.Modules) != len(expectModules) {
+		t.Fatalf("expect %d modules, actual %d", len(expectModules), len(s.Modules))
+	}
+
+	for i, m := range s.Modules {
+		if *m != expectModules[i] {
+			t.Errorf("%d: expect module %+v, actual %+v", i, expectModules[i], *m)
+		}
+	}
+
+	expectPlugins := []pluginSummary{
+		{Name: "Tools", Enabled: false, Referenced: true, Location: locationProject, Path: filepath.Join(projectDir, "Plugins/Tools/Tools.uplugin")},
+		{Name: "Paper2D", Enabled: true, Referenced: true, Location: locationEngine, Path: filepath.Join(engineDir, "Engine/Plugins/2D/Paper2D/Paper2D.uplugin")},
+		{Name: "Missing", Enabled: true, Referenced: true, Location: locationMissing},
+		{Name: "Hidden", Enabled: false, Referenced: false, Location: locationProject, Path: filepath.Join(projectDir, "Plugins/Hidden/Hidden.uplugin")},
+	}
+
+	if len(s.Plugins) != len(expectPlugins) {
+		t.Fatalf("expect %d plugins, actual %d", len(expectPlugins), len(s.Plugins))
+	}
+
+	for i, p := range s.Plugins {
+		if *p != expectPlugins[i] {
+			t.Errorf("%d: expect plugin %+v, actual %+v", i, expectPlugins[i], *p)
+		}
+	}
+
+	if s.EnginePluginCount != 1 {
+		t.Errorf("expect 1 engine plugin, actual %d", s.EnginePluginCount)
+	}
+}
@@ -0,0 +1,317 @@
+package lintcmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/zhiruili/urem/core"
+	"github.com/zhiruili/urem/descriptor"
+	"github.com/zhiruili/urem/diag"
+	"github.com/zhiruili/urem/infocmd"
+	"github.com/zhiruili/urem/osutil"
+	"github.com/zhiruili/urem/unreal"
+)
+
+// 描述文件支持的最新版本，参考 UE 中的 EProjectDescriptorVersion 和 EPluginDescriptorVersion。
+const latestFileVersion = 3
+
+// 支持的输出格式。
+const (
+	formatText = "text"
+	formatJSON = "json"
+)
+
+var availableFormats = []string{formatText, formatJSON}
+
+// LintDescriptorCmd 是用于检查 .uproject 和 .uplugin 文件的子命令。
+type LintDescriptorCmd struct {
+	Format         string `arg:"-f,--format" help:"output format text/json" default:"text"`
+	DescriptorFile string `arg:"positional,required" help:".uproject or .uplugin file, or a dir under it"`
+}
+
+// descriptorInfo 是检查时需要用到的 .uproject 和 .uplugin 文件的公共部分。
+type descriptorInfo struct {
+	file        string
+	fileVersion int
+	modules     []*descriptor.ModuleDescriptor
+	plugins     []*descriptor.PluginReference
+}
+
+// loadDescriptorInfo 根据后缀读取 .uproject 或 .uplugin 文件。
+func loadDescriptorInfo(file string) (*descriptorInfo, error) {
+	info := &descriptorInfo{file: file}
+	if filepath.Ext(file) == ".uplugin" {
+		d, err := descriptor.LoadPlugin(file)
+		if err != nil {
+			return nil, err
+		}
+
+		info.fileVersion, info.modules, info.plugins = d.FileVersion, d.Modules, d.Plugins
+		return info, nil
+	}
+
+	d, err := descriptor.LoadProject(file)
+	if err != nil {
+		return nil, err
+	}
+
+	info.fileVersion, info.modules, info.plugins = d.FileVersion, d.Modules, d.Plugins
+	return info, nil
+}
+
+// descriptorLinter 检查描述文件，收集发现的问题。
+type descriptorLinter struct {
+	info             *descriptorInfo
+	moduleFiles      []string        // 描述文件所在目录的 Source 下的所有 *.Build.cs
+	availablePlugins map[string]bool // 工程和引擎中存在的插件，为 nil 时不检查插件是否存在
+	diagnostics      []*diag.Diagnostic
+}
+
+func (l *descriptorLinter) report(severity diag.Severity, code string, module string, f string, a ...interface{}) {
+	l.diagnostics = append(l.diagnostics, &diag.Diagnostic{
+		File:     l.info.file,
+		Severity: severity,
+		Code:     code,
+		Message:  fmt.Sprintf(f, a...),
+		Module:   module,
+		Tool:     "urem",
+	})
+}
+
+func (l *descriptorLinter) checkFileVersion() {
+	v := l.info.fileVersion
+	if v == 0 {
+		l.report(diag.SeverityError, "invalid-file-version", "", "missing FileVersion")
+	} else if v < 1 || v > latestFileVersion {
+		l.report(diag.SeverityError, "invalid-file-version", "", "illegal FileVersion %d, must be between 1 and %d",
+			v, latestFileVersion)
+	}
+}
+
+func (l *descriptorLinter) checkModules() {
+	// 只有放在和 module 同名的目录中的 *.Build.cs 才算 module 的描述文件
+	moduleFiles := map[string]string{}
+	for _, file := range l.moduleFiles {
+		if name := unreal.ModuleNameOfFile(file); filepath.Base(filepath.Dir(file)) == name {
+			moduleFiles[name] = file
+		}
+	}
+
+	declared := map[string]bool{}
+	for i, m := range l.info.modules {
+		if len(m.Name) == 0 {
+			l.report(diag.SeverityError, "missing-module-name", "", "module #%d has no Name", i)
+			continue
+		}
+
+		if declared[m.Name] {
+			l.report(diag.SeverityError, "duplicate-module", m.Name, "module %s is declared more than once", m.Name)
+			continue
+		}
+
+		declared[m.Name] = true
+		if len(m.Type) == 0 {
+			l.report(diag.SeverityError, "invalid-module-type", m.Name, "module %s has no Type", m.Name)
+		} else if !infocmd.IsLegalModuleType(m.Type) {
+			l.report(diag.SeverityError, "invalid-module-type", m.Name, "module %s has illegal Type \"%s\", must be oneof: %s",
+				m.Name, m.Type, infocmd.GetFmtAvailableModuleTypes(", "))
+		}
+
+		if len(m.LoadingPhase) != 0 && !infocmd.IsLegalLoadingPhase(m.LoadingPhase) {
+			l.report(diag.SeverityError, "invalid-loading-phase", m.Name, "module %s has illegal LoadingPhase \"%s\", must be oneof: %s",
+				m.Name, m.LoadingPhase, infocmd.GetFmtAvailableLoadingPhases(", "))
+		}
+
+		if _, ok := moduleFiles[m.Name]; !ok {
+			l.report(diag.SeverityError, "missing-build-file", m.Name, "module %s has no %s/%s.Build.cs under Source",
+				m.Name, m.Name, m.Name)
+		}
+	}
+
+	for _, file := range l.moduleFiles {
+		name := unreal.ModuleNameOfFile(file)
+		if !declared[name] {
+			// 没有声明的 module 仍然可能作为其他 module 的依赖被编译，所以只是警告
+			l.report(diag.SeverityWarning, "unlisted-module", name, "module %s in %s is not listed in Modules",
+				name, filepath.Dir(file))
+		}
+	}
+}
+
+func (l *descriptorLinter) checkPlugins() {
+	referenced := map[string]bool{}
+	for i, p := range l.info.plugins {
+		if len(p.Name) == 0 {
+			l.report(diag.SeverityError, "missing-plugin-name", "", "plugin reference #%d has no Name", i)
+			continue
+		}
+
+		if referenced[p.Name] {
+			l.report(diag.SeverityWarning, "duplicate-plugin", "", "plugin %s is referenced more than once", p.Name)
+			continue
+		}
+
+		referenced[p.Name] = true
+		if l.availablePlugins == nil || !p.Enabled || l.availablePlugins[p.Name] {
+			continue
+		}
+
+		if p.Optional {
+			l.report(diag.SeverityWarning, "missing-plugin", "", "optional plugin %s is not found", p.Name)
+		} else {
+			l.report(diag.SeverityError, "missing-plugin", "", "plugin %s is enabled but not found", p.Name)
+		}
+	}
+}
+
+// lint 执行所有的检查并返回发现的问题。
+func (l *descriptorLinter) lint() []*diag.Diagnostic {
+	l.checkFileVersion()
+	l.checkModules()
+	l.checkPlugins()
+	return l.diagnostics
+}
+
+// addPlugins 将目录中的插件加入 plugins。
+func addPlugins(plugins map[string]bool, dir string) error {
+	files, err := unreal.FindPluginFiles(dir)
+	if err != nil {
+		return fmt.Errorf("find plugins in %s: %w", dir, err)
+	}
+
+	for _, file := range files {
+		plugins[unreal.PluginNameOfFile(file)] = true
+	}
+
+	return nil
+}
+
+// findAvailablePlugins 查找工程可以使用的所有插件，包括工程 Plugins 目录、AdditionalPluginDirectories
+// 和引擎中的插件。找不到工程或引擎时返回 nil，此时不检查插件是否存在。
+func findAvailablePlugins(descriptorFile string) (map[string]bool, error) {
+	projectFile := descriptorFile
+	if filepath.Ext(descriptorFile) != ".uproject" {
+		found, err := osutil.FindFileBottomUp(filepath.Dir(filepath.Dir(descriptorFile)), "*.uproject")
+		if err != nil {
+			return nil, fmt.Errorf("find .uproject file: %w", err)
+		}
+
+		if len(found) == 0 {
+			core.LogE("warning: .uproject file not found, skip checking plugin references")
+			return nil, nil
+		}
+
+		projectFile = found
+	}
+
+	projectInfo := &unreal.ProjectInfo{ProjectFilePath: projectFile}
+	engine, err := unreal.FindProjectEngineInfo(projectInfo)
+	if err != nil {
+		core.LogE("warning: find Unreal engine info: %s, skip checking plugin references", err.Error())
+		return nil, nil
+	}
+
+	project, err := descriptor.LoadProject(projectFile)
+	if err != nil {
+		return nil, err
+	}
+
+	projectPluginFiles, err := projectInfo.FindProjectPluginFiles(project.AdditionalPluginDirectories)
+	if err != nil {
+		return nil, err
+	}
+
+	plugins := map[string]bool{}
+	for _, file := range projectPluginFiles {
+		plugins[unreal.PluginNameOfFile(file)] = true
+	}
+
+	if err := addPlugins(plugins, engine.EnginePluginsDir()); err != nil {
+		return nil, err
+	}
+
+	return plugins, nil
+}
+
+// writeText 以 file: severity: message [code] 的格式输出问题，并在最后输出统计信息。
+func writeText(w io.Writer, diagnostics []*diag.Diagnostic) {
+	for _, d := range diagnostics {
+		fmt.Fprintf(w, "%s: %s: %s [%s]\n", d.File, d.Severity, d.Message, d.Code)
+	}
+
+	if len(diagnostics) == 0 {
+		fmt.Fprintln(w, "no problem found")
+	} else {
+		fmt.Fprintf(w, "%d errors, %d warnings\n", diag.Count(diagnostics, diag.SeverityError),
+			diag.Count(diagnostics, diag.SeverityWarning))
+	}
+}
+
+func (cmd *LintDescriptorCmd) checkArgs() error {
+	if !core.StrContains(availableFormats, cmd.Format) {
+		return core.IllegalArgErrorf("Format", "illegal value, must be oneof: %s", strings.Join(availableFormats, ", "))
+	}
+
+	return nil
+}
+
+// Run 执行描述文件的检查，有错误时返回 error，便于在 CI 中使用。
+func (cmd *LintDescriptorCmd) Run() error {
+	if err := cmd.checkArgs(); err != nil {
+		return err
+	}
+
+	// 直接指定了描述文件时使用该文件，否则向上查找
+	file := cmd.DescriptorFile
+	ext := filepath.Ext(file)
+	var err error
+	if isDir, _ := osutil.IsDir(file); isDir || (ext != ".uproject" && ext != ".uplugin") {
+		file, err = osutil.FindFileBottomUp(cmd.DescriptorFile, "*.uproject", "*.uplugin")
+	}
+
+	if err != nil {
+		return fmt.Errorf("find .uproject or .uplugin file: %w", err)
+	}
+
+	if file == "" {
+		return fmt.Errorf(".uproject or .uplugin file no found")
+	}
+
+	if file, err = filepath.Abs(file); err != nil {
+		return fmt.Errorf("illegal descriptor file path: %s", file)
+	}
+
+	info, err := loadDescriptorInfo(file)
+	if err != nil {
+		return err
+	}
+
+	moduleFiles, err := unreal.FindModuleFiles(filepath.Join(filepath.Dir(file), "Source"))
+	if err != nil {
+		return fmt.Errorf("find modules: %w", err)
+	}
+
+	availablePlugins, err := findAvailablePlugins(file)
+	if err != nil {
+		return err
+	}
+
+	linter := &descriptorLinter{info: info, moduleFiles: moduleFiles, availablePlugins: availablePlugins}
+	diagnostics := linter.lint()
+	if cmd.Format == formatJSON {
+		if err := diag.WriteJSON(os.Stdout, diagnostics); err != nil {
+			return err
+		}
+	} else {
+		writeText(os.Stdout, diagnostics)
+	}
+
+	if n := diag.Count(diagnostics, diag.SeverityError); n != 0 {
+		return fmt.Errorf("%d errors found in %s", n, file)
+	}
+
+	return nil
+}
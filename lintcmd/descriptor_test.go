@@ -0,0 +1,112 @@
+package lintcmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/zhiruili/urem/descriptor"
+)
+
+// TestDescriptorLinter 测试描述文件的各项检查。
+func TestDescriptorLinter(t *testing.T) {
+	cases := []struct {
+		name        string
+		info        descriptorInfo
+		moduleFiles []string
+		plugins     map[string]bool
+		expect      []string
+	}{
+		{
+			name: "valid",
+			info: descriptorInfo{
+				fileVersion: 3,
+				modules:     []*descriptor.ModuleDescriptor{{Name: "Game", Type: "Runtime", LoadingPhase: "Default"}},
+				plugins:     []*descriptor.PluginReference{{Name: "Tools", Enabled: true}},
+			},
+			moduleFiles: []string{"/p/Source/Game/Game.Build.cs"},
+			plugins:     map[string]bool{"Tools": true},
+			expect:      nil,
+		},
+		{
+			name:   "file version",
+			info:   descriptorInfo{fileVersion: 4},
+			expect: []string{"invalid-file-version"},
+		},
+		{
+			name: "illegal module",
+			info: descriptorInfo{
+				fileVersion: 3,
+				modules: []*descriptor.ModuleDescriptor{
+					{Name: "Game", Type: "Runtim", LoadingPhase: "Later"},
+					{Name: "Game", Type: "Runtime"},
+					{Type: "Runtime"},
+				},
+			},
+			moduleFiles: []string{"/p/Source/Game/Game.Build.cs"},
+			expect:      []string{"invalid-module-type", "invalid-loading-phase", "duplicate-module", "missing-module-name"},
+		},
+		{
+			name: "module files mismatch",
+			info: descriptorInfo{
+				fileVersion: 3,
+				modules:     []*descriptor.ModuleDescriptor{{Name: "Game", Type: "Runtime"}},
+			},
+			moduleFiles: []string{"/p/Source/Other/Other.build.cs"},
+			expect:      []string{"missing-build-file", "unlisted-module"},
+		},
+		{
+			name: "module file in other dir",
+			info: descriptorInfo{
+				fileVersion: 3,
+				modules:     []*descriptor.ModuleDescriptor{{Name: "Game", Type: "Runtime"}},
+			},
+			moduleFiles: []string{"/p/Source/Other/Game.Build.cs"},
+			expect:      []string{"missing-build-file"},
+		},
+		{
+			name: "nested module dir",
+			info: descriptorInfo{
+				fileVersion: 3,
+				modules:     []*descriptor.ModuleDescriptor{{Name: "Game", Type: "Runtime"}},
+			},
+			moduleFiles: []string{"/p/Source/Runtime/Game/Game.Build.cs"},
+			expect:      nil,
+		},
+		{
+			name: "plugin references",
+			info: descriptorInfo{
+				fileVersion: 3,
+				plugins: []*descriptor.PluginReference{
+					{Name: "Missing", Enabled: true},
+					{Name: "Missing", Enabled: true},
+					{Name: "Optional", Enabled: true, Optional: true},
+					{Name: "Disabled", Enabled: false},
+				},
+			},
+			plugins: map[string]bool{},
+			expect:  []string{"missing-plugin", "duplicate-plugin", "missing-plugin"},
+		},
+		{
+			name: "engine not found",
+			info: descriptorInfo{
+				fileVersion: 3,
+				plugins:     []*descriptor.PluginReference{{Name: "Missing", Enabled: true}},
+			},
+			plugins: nil,
+			expect:  nil,
+		},
+	}
+
+	for i, c := range cases {
+		info := c.info
+		linter := &descriptorLinter{info: &info, moduleFiles: c.moduleFiles, availablePlugins: c.plugins}
+		var actual []string
+		for _, d := range linter.lint() {
+			actual = append(actual, d.Code)
+		}
+
+		if !reflect.DeepEqual(actual, c.expect) {
+			t.Errorf("%d:%s: expect %v, actual %v", i, c.name, c.expect, actual)
+		}
+	}
+}
@@ -0,0 +1,19 @@
+package lintcmd
+
+import (
+	"fmt"
+)
+
+// Cmd 是 lint 子命令的集合。
+type Cmd struct {
+	DescriptorCommand *LintDescriptorCmd `arg:"subcommand:descriptor" help:"validate .uproject or .uplugin file."`
+}
+
+// Run 实现了 subCmd 的接口。
+func (cmd *Cmd) Run() error {
+	if cmd.DescriptorCommand != nil {
+		return cmd.DescriptorCommand.Run()
+	}
+
+	return fmt.Errorf("missing subcommand of lint cmd")
+}
@@ -7,10 +7,17 @@ import (
 	"runtime/pprof"
 
 	"github.com/alexflint/go-arg"
+	"github.com/zhiruili/urem/buildcmd"
+	"github.com/zhiruili/urem/cleancmd"
 	"github.com/zhiruili/urem/core"
+	"github.com/zhiruili/urem/depscmd"
+	"github.com/zhiruili/urem/enginecmd"
 	"github.com/zhiruili/urem/gencmd"
 	"github.com/zhiruili/urem/infocmd"
+	"github.com/zhiruili/urem/lintcmd"
 	"github.com/zhiruili/urem/newcmd"
+	"github.com/zhiruili/urem/switchcmd"
+	"github.com/zhiruili/urem/watchcmd"
 )
 
 type subCmd interface {
@@ -28,13 +35,27 @@ var (
 	_ subCmd = (*newcmd.Cmd)(nil)
 	_ subCmd = (*gencmd.Cmd)(nil)
 	_ subCmd = (*infocmd.Cmd)(nil)
+	_ subCmd = (*enginecmd.Cmd)(nil)
+	_ subCmd = (*switchcmd.Cmd)(nil)
+	_ subCmd = (*buildcmd.Cmd)(nil)
+	_ subCmd = (*cleancmd.Cmd)(nil)
+	_ subCmd = (*watchcmd.Cmd)(nil)
+	_ subCmd = (*lintcmd.Cmd)(nil)
+	_ subCmd = (*depscmd.Cmd)(nil)
 	_ subCmd = (*dummyCmd)(nil)
 )
 
 type args struct {
-	NewCommand  *newcmd.Cmd  `arg:"subcommand:new"`
-	GenCommand  *gencmd.Cmd  `arg:"subcommand:gen"`
-	InfoCommand *infocmd.Cmd `arg:"subcommand:info"`
+	NewCommand    *newcmd.Cmd    `arg:"subcommand:new"`
+	GenCommand    *gencmd.Cmd    `arg:"subcommand:gen"`
+	InfoCommand   *infocmd.Cmd   `arg:"subcommand:info"`
+	EngineCommand *enginecmd.Cmd `arg:"subcommand:engine"`
+	SwitchCommand *switchcmd.Cmd `arg:"subcommand:switch-engine"`
+	BuildCommand  *buildcmd.Cmd  `arg:"subcommand:build"`
+	CleanCommand  *cleancmd.Cmd  `arg:"subcommand:clean"`
+	WatchCommand  *watchcmd.Cmd  `arg:"subcommand:watch"`
+	LintCommand   *lintcmd.Cmd   `arg:"subcommand:lint"`
+	DepsCommand   *depscmd.Cmd   `arg:"subcommand:deps"`
 
 	core.Args
 }
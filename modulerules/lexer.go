@@ -0,0 +1,208 @@
+package modulerules
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// tokenKind 是词法单元的类型。
+type tokenKind int
+
+const (
+	tokIdent  tokenKind = iota // 标识符和关键字
+	tokString                  // 普通字符串和 @"" 字符串
+	tokInterp                  // $"" 插值字符串，无法静态求值
+	tokNumber                  // 数字
+	tokChar                    // 字符
+	tokPunct                   // 运算符和分隔符
+)
+
+// token 是一个词法单元，start 和 end 是在源码中的范围。
+type token struct {
+	kind  tokenKind
+	text  string // 原文
+	value string // 字符串转义后的值
+	start int
+	end   int
+}
+
+func (t token) is(kind tokenKind, text string) bool {
+	return t.kind == kind && t.text == text
+}
+
+func (t token) isPunct(text string) bool {
+	return t.is(tokPunct, text)
+}
+
+func (t token) isIdent(text string) bool {
+	return t.is(tokIdent, text)
+}
+
+// twoCharPuncts 是需要作为一个整体的双字符运算符。
+var twoCharPuncts = []string{"==", "!=", "&&", "||", "+=", "-=", "=>", "<=", ">=", "++", "--", "??", "::"}
+
+func isIdentStart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
+
+func isIdentPart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// lexer 是一个宽松的 C# 词法分析器，只识别解析 *.Build.cs 需要的部分，遇到无法识别的字符时跳过。
+type lexer struct {
+	src  string
+	pos  int
+	toks []token
+}
+
+// tokenize 将源码拆分为词法单元，跳过空白、注释和预处理指令。
+func tokenize(src string) []token {
+	l := &lexer{src: src}
+	lineStart := true
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		switch {
+		case c == '\n':
+			l.pos++
+			lineStart = true
+			continue
+		case c == ' ' || c == '\t' || c == '\r':
+			l.pos++
+			continue
+		case c == '#' && lineStart:
+			l.skipLine()
+			continue
+		case strings.HasPrefix(l.src[l.pos:], "//"):
+			l.skipLine()
+			continue
+		case strings.HasPrefix(l.src[l.pos:], "/*"):
+			if end := strings.Index(l.src[l.pos+2:], "*/"); end >= 0 {
+				l.pos += end + 4
+			} else {
+				l.pos = len(l.src)
+			}
+			continue
+		}
+
+		lineStart = false
+		l.lexToken()
+	}
+
+	return l.toks
+}
+
+func (l *lexer) skipLine() {
+	if end := strings.IndexByte(l.src[l.pos:], '\n'); end >= 0 {
+		l.pos += end
+	} else {
+		l.pos = len(l.src)
+	}
+}
+
+func (l *lexer) emit(kind tokenKind, start int, value string) {
+	l.toks = append(l.toks, token{kind: kind, text: l.src[start:l.pos], value: value, start: start, end: l.pos})
+}
+
+func (l *lexer) lexToken() {
+	start := l.pos
+	rest := l.src[l.pos:]
+	r, size := utf8.DecodeRuneInString(rest)
+	switch {
+	case strings.HasPrefix(rest, "$@\"") || strings.HasPrefix(rest, "@$\""):
+		l.pos += 2
+		l.emit(tokInterp, start, l.lexVerbatimString())
+	case strings.HasPrefix(rest, "@\""):
+		l.pos++
+		l.emit(tokString, start, l.lexVerbatimString())
+	case strings.HasPrefix(rest, "$\""):
+		l.pos++
+		l.emit(tokInterp, start, l.lexString('"'))
+	case r == '"':
+		l.emit(tokString, start, l.lexString('"'))
+	case r == '\'':
+		l.emit(tokChar, start, l.lexString('\''))
+	case r == '@' || isIdentStart(r):
+		l.pos += size
+		for l.pos < len(l.src) {
+			r, size := utf8.DecodeRuneInString(l.src[l.pos:])
+			if !isIdentPart(r) {
+				break
+			}
+			l.pos += size
+		}
+		l.emit(tokIdent, start, "")
+	case unicode.IsDigit(r):
+		for l.pos < len(l.src) && (isIdentPart(rune(l.src[l.pos])) || l.src[l.pos] == '.') {
+			l.pos++
+		}
+		l.emit(tokNumber, start, "")
+	default:
+		l.pos += size
+		for _, p := range twoCharPuncts {
+			if strings.HasPrefix(rest, p) {
+				l.pos = start + len(p)
+				break
+			}
+		}
+		l.emit(tokPunct, start, "")
+	}
+}
+
+// lexString 读取以 quote 包围的普通字符串，返回转义后的值。
+func (l *lexer) lexString(quote byte) string {
+	var sb strings.Builder
+	l.pos++
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		switch {
+		case c == quote:
+			l.pos++
+			return sb.String()
+		case c == '\n':
+			// 字符串没有闭合，到行尾结束
+			return sb.String()
+		case c == '\\' && l.pos+1 < len(l.src):
+			l.pos++
+			switch e := l.src[l.pos]; e {
+			case 'n':
+				sb.WriteByte('\n')
+			case 't':
+				sb.WriteByte('\t')
+			case 'r':
+				sb.WriteByte('\r')
+			case '0':
+				sb.WriteByte(0)
+			default:
+				sb.WriteByte(e)
+			}
+			l.pos++
+		default:
+			sb.WriteByte(c)
+			l.pos++
+		}
+	}
+
+	return sb.String()
+}
+
+// lexVerbatimString 读取 @"" 字符串，其中两个连续的双引号表示一个双引号。
+func (l *lexer) lexVerbatimString() string {
+	var sb strings.Builder
+	l.pos++
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		l.pos++
+		if c != '"' {
+			sb.WriteByte(c)
+		} else if l.pos < len(l.src) && l.src[l.pos] == '"' {
+			sb.WriteByte('"')
+			l.pos++
+		} else {
+			break
+		}
+	}
+
+	return sb.String()
+}
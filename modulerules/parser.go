@@ -0,0 +1,585 @@
+package modulerules
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/zhiruili/urem/core"
+)
+
+// condition 是 if 等语句的条件，只有形如 Target.Platform == UnrealTargetPlatform.Win64 的平台判断可以求值。
+type condition struct {
+	text      string
+	known     bool     // 是否是可以求值的平台判断
+	platforms []string // 条件成立的平台
+	excluded  []string // 条件不成立的平台
+}
+
+// negate 获取 else 分支对应的条件。
+func (c *condition) negate() *condition {
+	return &condition{
+		text:      "!(" + c.text + ")",
+		known:     c.known,
+		platforms: c.excluded,
+		excluded:  c.platforms,
+	}
+}
+
+// loopKeywords 是后面跟着括号和语句的关键字，其中的语句是否执行、执行几次都无法静态确定。
+var loopKeywords = []string{"for", "foreach", "while", "switch", "using", "lock", "catch", "fixed"}
+
+// parser 按语句解析 *.Build.cs，只关心 module 规则的赋值和 Add/AddRange 调用，其他语句都会被跳过。
+type parser struct {
+	src       string
+	toks      []token
+	pos       int
+	conds     []*condition
+	className string // 当前所在的类，用于识别构造函数
+	rules     *ModuleRules
+}
+
+func newParser(src string) *parser {
+	return &parser{src: src, toks: tokenize(src), rules: &ModuleRules{}}
+}
+
+func (p *parser) eof() bool {
+	return p.pos >= len(p.toks)
+}
+
+func (p *parser) peek() token {
+	if p.eof() {
+		return token{kind: tokPunct}
+	}
+
+	return p.toks[p.pos]
+}
+
+func (p *parser) peekAt(offset int) token {
+	if p.pos+offset >= len(p.toks) {
+		return token{kind: tokPunct}
+	}
+
+	return p.toks[p.pos+offset]
+}
+
+// text 获取一组词法单元对应的源码，空白会被合并为一个空格。
+func (p *parser) text(toks []token) string {
+	if len(toks) == 0 {
+		return ""
+	}
+
+	return strings.Join(strings.Fields(p.src[toks[0].start:toks[len(toks)-1].end]), " ")
+}
+
+// withCondition 在条件 c 下执行 f。
+func (p *parser) withCondition(c *condition, f func()) {
+	p.conds = append(p.conds, c)
+	f()
+	p.conds = p.conds[:len(p.conds)-1]
+}
+
+// parseBlock 解析语句直到遇到 } 或者结束。
+func (p *parser) parseBlock() {
+	for !p.eof() {
+		if p.peek().isPunct("}") {
+			p.pos++
+			return
+		}
+
+		p.parseStatement()
+	}
+}
+
+// readParens 读取当前位置的括号中的内容，当前位置不是 ( 时返回 nil。
+func (p *parser) readParens() []token {
+	if !p.peek().isPunct("(") {
+		return nil
+	}
+
+	start := p.pos + 1
+	depth := 0
+	for ; !p.eof(); p.pos++ {
+		t := p.peek()
+		if t.isPunct("(") {
+			depth++
+		} else if t.isPunct(")") {
+			depth--
+			if depth == 0 {
+				p.pos++
+				return p.toks[start : p.pos-1]
+			}
+		}
+	}
+
+	return p.toks[start:]
+}
+
+// parseStatement 解析一条语句，可能是代码块、if 语句、循环、声明或者普通的语句。
+func (p *parser) parseStatement() {
+	t := p.peek()
+	switch {
+	case t.isPunct("{"):
+		p.pos++
+		p.parseBlock()
+	case t.isPunct(";"):
+		p.pos++
+	case t.isIdent("if"):
+		p.pos++
+		c := p.parseCondition(p.readParens())
+		p.withCondition(c, p.parseStatement)
+		if p.peek().isIdent("else") {
+			p.pos++
+			p.withCondition(c.negate(), p.parseStatement)
+		}
+	case t.kind == tokIdent && core.StrContains(loopKeywords, t.text) && p.peekAt(1).isPunct("("):
+		p.pos++
+		header := p.readParens()
+		if p.peek().isPunct(";") {
+			// do { } while (...); 的结尾
+			p.pos++
+			return
+		}
+
+		c := &condition{text: t.text + " (" + p.text(header) + ")"}
+		p.withCondition(c, p.parseStatement)
+	case t.isIdent("do") || t.isIdent("else"):
+		p.pos++
+		p.withCondition(&condition{text: t.text}, p.parseStatement)
+	case t.isIdent("try") || t.isIdent("finally") || t.isIdent("unsafe") || t.isIdent("checked"):
+		p.pos++
+		p.parseStatement()
+	case (t.isIdent("case") || t.isIdent("default")) && !p.peekAt(1).isPunct("("):
+		for !p.eof() && !p.peek().isPunct(":") {
+			p.pos++
+		}
+		p.pos++
+	default:
+		toks, isHeader := p.readStatement()
+		if isHeader {
+			p.pos++
+			p.parseDeclaration(toks)
+		} else {
+			p.analyzeStatement(toks)
+		}
+	}
+}
+
+// readStatement 读取到 ; 为止的语句。如果先遇到了不属于初始化表达式的 {，说明是类、方法等声明的头部，
+// 此时返回 true，当前位置停在 { 上。为了在括号不匹配时也能继续解析，不在花括号中的 ; 总是会结束语句。
+func (p *parser) readStatement() ([]token, bool) {
+	start := p.pos
+	parenDepth := 0
+	braceDepth := 0
+	isInitializer := false
+	for ; !p.eof(); p.pos++ {
+		t := p.peek()
+		if t.kind == tokPunct {
+			switch t.text {
+			case "(", "[":
+				parenDepth++
+			case ")", "]":
+				parenDepth--
+			case "=", "+=", "-=":
+				isInitializer = true
+			case "{":
+				if parenDepth <= 0 && braceDepth == 0 && !isInitializer {
+					return p.toks[start:p.pos], true
+				}
+				braceDepth++
+			case "}":
+				if braceDepth == 0 {
+					return p.toks[start:p.pos], false
+				}
+				braceDepth--
+			case ";":
+				if braceDepth == 0 {
+					p.pos++
+					return p.toks[start : p.pos-1], false
+				}
+			}
+		} else if t.isIdent("new") {
+			isInitializer = true
+		}
+	}
+
+	return p.toks[start:], false
+}
+
+// parseDeclaration 解析声明的代码块。类和构造函数中的语句总是会执行，其他方法中的语句标记为 conditional。
+func (p *parser) parseDeclaration(header []token) {
+	for i, t := range header {
+		if (t.isIdent("class") || t.isIdent("struct")) && i+1 < len(header) {
+			name := header[i+1].text
+			isRules := false
+			for _, h := range header[i+1:] {
+				if h.isIdent("ModuleRules") {
+					isRules = true
+				}
+			}
+
+			if len(p.rules.Name) == 0 && isRules {
+				p.rules.Name = name
+			}
+
+			outer := p.className
+			p.className = name
+			p.parseBlock()
+			p.className = outer
+			return
+		}
+
+		if t.isIdent("namespace") {
+			p.parseBlock()
+			return
+		}
+
+		if t.isPunct("(") && i > 0 && header[i-1].text == p.className {
+			p.parseBlock()
+			return
+		}
+	}
+
+	p.withCondition(&condition{text: "in " + p.text(header)}, p.parseBlock)
+}
+
+// parseCondition 解析 if 的条件，支持用 || 连接的 == 平台判断和用 && 连接的 != 平台判断。
+func (p *parser) parseCondition(toks []token) *condition {
+	toks = stripParens(toks)
+	c := &condition{text: p.text(toks)}
+	if parts := splitTokens(toks, "||"); len(parts) > 1 {
+		for _, part := range parts {
+			platform, op, ok := platformComparison(stripParens(part))
+			if !ok || op != "==" {
+				return c
+			}
+			c.platforms = append(c.platforms, platform)
+		}
+
+		c.known = true
+		return c
+	}
+
+	if parts := splitTokens(toks, "&&"); len(parts) > 1 {
+		for _, part := range parts {
+			platform, op, ok := platformComparison(stripParens(part))
+			if !ok || op != "!=" {
+				return c
+			}
+			c.excluded = append(c.excluded, platform)
+		}
+
+		c.known = true
+		return c
+	}
+
+	if platform, op, ok := platformComparison(toks); ok {
+		c.known = true
+		if op == "==" {
+			c.platforms = []string{platform}
+		} else {
+			c.excluded = []string{platform}
+		}
+	}
+
+	return c
+}
+
+// platformComparison 识别 Target.Platform == UnrealTargetPlatform.Win64 形式的比较，左右可以交换。
+func platformComparison(toks []token) (string, string, bool) {
+	isTarget := func(ts []token) bool {
+		return len(ts) == 3 && ts[0].isIdent("Target") && ts[1].isPunct(".") && ts[2].isIdent("Platform")
+	}
+
+	platformOf := func(ts []token) (string, bool) {
+		if len(ts) == 3 && ts[0].isIdent("UnrealTargetPlatform") && ts[1].isPunct(".") && ts[2].kind == tokIdent {
+			return ts[2].text, true
+		}
+		return "", false
+	}
+
+	for i, t := range toks {
+		if !t.isPunct("==") && !t.isPunct("!=") {
+			continue
+		}
+
+		left, right := toks[:i], toks[i+1:]
+		if isTarget(right) {
+			left, right = right, left
+		}
+
+		if !isTarget(left) {
+			return "", "", false
+		}
+
+		platform, ok := platformOf(right)
+		return platform, t.text, ok
+	}
+
+	return "", "", false
+}
+
+// stripParens 去掉包围整个表达式的括号。
+func stripParens(toks []token) []token {
+	for len(toks) >= 2 && toks[0].isPunct("(") && toks[len(toks)-1].isPunct(")") {
+		depth := 0
+		for i, t := range toks {
+			if t.isPunct("(") {
+				depth++
+			} else if t.isPunct(")") {
+				depth--
+			}
+
+			if depth == 0 && i != len(toks)-1 {
+				return toks
+			}
+		}
+
+		toks = toks[1 : len(toks)-1]
+	}
+
+	return toks
+}
+
+// splitTokens 在不被括号包围的 sep 处拆分词法单元，忽略空的部分。
+func splitTokens(toks []token, sep string) [][]token {
+	var parts [][]token
+	depth := 0
+	start := 0
+	for i, t := range toks {
+		if t.kind != tokPunct {
+			continue
+		}
+
+		switch t.text {
+		case "(", "[", "{":
+			depth++
+		case ")", "]", "}":
+			depth--
+		case sep:
+			if depth == 0 {
+				if i > start {
+					parts = append(parts, toks[start:i])
+				}
+				start = i + 1
+			}
+		}
+	}
+
+	if start < len(toks) {
+		parts = append(parts, toks[start:])
+	}
+
+	return parts
+}
+
+// analyzeStatement 识别规则的赋值和 Add/AddRange 调用。
+func (p *parser) analyzeStatement(toks []token) {
+	if len(toks) >= 2 && (toks[0].isIdent("this") || toks[0].isIdent("base")) && toks[1].isPunct(".") {
+		toks = toks[2:]
+	}
+
+	if len(toks) < 3 || toks[0].kind != tokIdent {
+		return
+	}
+
+	name := toks[0].text
+	if toks[1].isPunct("=") {
+		p.analyzeAssignment(name, toks[2:])
+		return
+	}
+
+	list, ok := p.rules.lists()[name]
+	if !ok || len(toks) < 5 || !toks[1].isPunct(".") || !toks[3].isPunct("(") {
+		return
+	}
+
+	// 去掉调用的右括号，括号不匹配时也尽量解析参数
+	args := toks[4:]
+	for len(args) != 0 && args[len(args)-1].isPunct(")") && parenBalance(args) < 0 {
+		args = args[:len(args)-1]
+	}
+
+	switch toks[2].text {
+	case "Add":
+		*list = append(*list, p.newItem(args))
+	case "AddRange":
+		if elements, ok := initializerElements(args); ok {
+			for _, element := range elements {
+				*list = append(*list, p.newItem(element))
+			}
+		} else {
+			item := p.newItem(args)
+			item.Conditional = true
+			item.Expression = true
+			*list = append(*list, item)
+		}
+	}
+}
+
+// analyzeAssignment 识别 PCHUsage、bool 规则和列表规则的赋值。
+func (p *parser) analyzeAssignment(name string, value []token) {
+	if name == "PCHUsage" {
+		// PCHUsage = PCHUsageMode.UseExplicitOrSharedPCHs 或者 ModuleRules.PCHUsageMode.UseExplicitOrSharedPCHs
+		item := p.newConditionalItem()
+		if isQualifiedName(value) {
+			item.Value = value[len(value)-1].text
+		} else {
+			item.Value = p.text(value)
+			item.Conditional = true
+			item.Expression = true
+		}
+		p.rules.PCHUsage = item
+		return
+	}
+
+	if list, ok := p.rules.lists()[name]; ok {
+		// 在条件中的赋值不一定生效，只能追加到已有的规则后面
+		if len(p.conds) == 0 {
+			*list = nil
+		}
+
+		if elements, ok := initializerElements(value); ok {
+			for _, element := range elements {
+				*list = append(*list, p.newItem(element))
+			}
+		} else {
+			// 如 = SharedDeps 或 = new List<string>(Other)，无法求值时记录表达式的原文
+			item := p.newConditionalItem()
+			item.Value = p.text(value)
+			item.Conditional = true
+			item.Expression = true
+			*list = append(*list, item)
+		}
+		return
+	}
+
+	runes := []rune(name)
+	if len(runes) >= 2 && runes[0] == 'b' && unicode.IsUpper(runes[1]) {
+		item := p.newConditionalItem()
+		if len(value) == 1 && (value[0].isIdent("true") || value[0].isIdent("false")) {
+			item.Value = value[0].text
+		} else {
+			item.Value = p.text(value)
+			item.Conditional = true
+			item.Expression = true
+		}
+		p.rules.Flags = append(p.rules.Flags, &Flag{Name: name, Item: *item})
+	}
+}
+
+// parenBalance 获取左括号比右括号多出的数量。
+func parenBalance(toks []token) int {
+	balance := 0
+	for _, t := range toks {
+		if t.isPunct("(") {
+			balance++
+		} else if t.isPunct(")") {
+			balance--
+		}
+	}
+
+	return balance
+}
+
+// isQualifiedName 检查表达式是否是 A.B.C 形式的名字。
+func isQualifiedName(toks []token) bool {
+	for i, t := range toks {
+		if (i%2 == 0 && t.kind != tokIdent) || (i%2 == 1 && !t.isPunct(".")) {
+			return false
+		}
+	}
+
+	return len(toks)%2 == 1
+}
+
+// initializerElements 获取 new string[] { "A", "B" } 形式的初始化表达式中的元素。
+func initializerElements(toks []token) ([][]token, bool) {
+	if len(toks) == 0 || !toks[0].isIdent("new") {
+		return nil, false
+	}
+
+	for i, t := range toks {
+		if t.isPunct("{") && toks[len(toks)-1].isPunct("}") {
+			return splitTokens(toks[i+1:len(toks)-1], ","), true
+		}
+	}
+
+	return nil, false
+}
+
+// evalString 计算由字符串字面量和 + 组成的表达式的值。
+func evalString(toks []token) (string, bool) {
+	var sb strings.Builder
+	for i, t := range toks {
+		if i%2 == 1 {
+			if !t.isPunct("+") {
+				return "", false
+			}
+		} else if t.kind == tokString {
+			sb.WriteString(t.value)
+		} else {
+			return "", false
+		}
+	}
+
+	return sb.String(), len(toks)%2 == 1
+}
+
+// newItem 根据表达式和当前所在的条件创建规则的值，表达式不是字符串时值为表达式的原文。
+func (p *parser) newItem(value []token) *Item {
+	item := p.newConditionalItem()
+	if s, ok := evalString(value); ok {
+		item.Value = s
+	} else {
+		item.Value = p.text(value)
+		item.Conditional = true
+		item.Expression = true
+	}
+
+	return item
+}
+
+// newConditionalItem 根据当前所在的条件创建一个没有值的规则。
+func (p *parser) newConditionalItem() *Item {
+	item := &Item{}
+	var texts []string
+	for _, c := range p.conds {
+		texts = append(texts, c.text)
+		if !c.known {
+			item.Conditional = true
+			continue
+		}
+
+		if len(c.platforms) != 0 {
+			if item.Platforms == nil {
+				item.Platforms = append([]string{}, c.platforms...)
+			} else {
+				item.Platforms = intersect(item.Platforms, c.platforms)
+				if len(item.Platforms) == 0 {
+					// 嵌套的平台判断互相矛盾，无法表示
+					item.Conditional = true
+				}
+			}
+		}
+
+		for _, platform := range c.excluded {
+			if !core.StrContains(item.ExcludedPlatforms, platform) {
+				item.ExcludedPlatforms = append(item.ExcludedPlatforms, platform)
+			}
+		}
+	}
+
+	item.Condition = joinCondition(texts)
+	return item
+}
+
+func intersect(a []string, b []string) []string {
+	result := []string{}
+	for _, s := range a {
+		if core.StrContains(b, s) {
+			result = append(result, s)
+		}
+	}
+
+	return result
+}
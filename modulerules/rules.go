@@ -0,0 +1,118 @@
+// Package modulerules 静态解析 UE module 的 *.Build.cs 文件，提取依赖、include 路径、宏定义等常用规则。
+// 解析是宽松的：不执行 C# 代码，只识别常见的写法，无法求值的值和条件会被标记为 conditional，而不是报错。
+// 参考 UE 的实现：
+// ModuleRules.cs
+package modulerules
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/zhiruili/urem/core"
+	"github.com/zhiruili/urem/unreal"
+)
+
+// Item 是规则中的一个值，以及它生效的条件。
+type Item struct {
+	Value             string   `json:"value"`                       // 字符串的值，无法求值时是表达式的原文
+	Platforms         []string `json:"platforms,omitempty"`         // 只在这些平台生效，为空时不限制
+	ExcludedPlatforms []string `json:"excludedPlatforms,omitempty"` // 在这些平台不生效
+	Condition         string   `json:"condition,omitempty"`         // 所在的 if 等语句的条件原文
+	Conditional       bool     `json:"conditional,omitempty"`       // 值或者条件无法静态求值
+	Expression        bool     `json:"expression,omitempty"`        // 值无法静态求值，Value 是表达式的原文
+}
+
+// AppliesTo 检查值在指定平台是否生效，conditional 的值按照生效处理。
+func (it *Item) AppliesTo(platform string) bool {
+	if len(it.Platforms) != 0 && !core.StrContains(it.Platforms, platform) {
+		return false
+	}
+
+	return !core.StrContains(it.ExcludedPlatforms, platform)
+}
+
+func (it *Item) String() string {
+	if len(it.Condition) == 0 {
+		return it.Value
+	}
+
+	return fmt.Sprintf("%s (if %s)", it.Value, it.Condition)
+}
+
+// Flag 是一个 bool 类型的规则，如 bEnableExceptions。
+type Flag struct {
+	Name string `json:"name"`
+	Item
+}
+
+// ModuleRules 是从 *.Build.cs 中解析出的 module 规则。
+type ModuleRules struct {
+	Name                          string  `json:"name"`
+	PublicDependencyModuleNames   []*Item `json:"publicDependencyModuleNames,omitempty"`
+	PrivateDependencyModuleNames  []*Item `json:"privateDependencyModuleNames,omitempty"`
+	DynamicallyLoadedModuleNames  []*Item `json:"dynamicallyLoadedModuleNames,omitempty"`
+	PublicIncludePathModuleNames  []*Item `json:"publicIncludePathModuleNames,omitempty"`
+	PrivateIncludePathModuleNames []*Item `json:"privateIncludePathModuleNames,omitempty"`
+	PublicIncludePaths            []*Item `json:"publicIncludePaths,omitempty"`
+	PrivateIncludePaths           []*Item `json:"privateIncludePaths,omitempty"`
+	PublicSystemIncludePaths      []*Item `json:"publicSystemIncludePaths,omitempty"`
+	PublicDefinitions             []*Item `json:"publicDefinitions,omitempty"`
+	PrivateDefinitions            []*Item `json:"privateDefinitions,omitempty"`
+	PCHUsage                      *Item   `json:"pchUsage,omitempty"`
+	Flags                         []*Flag `json:"flags,omitempty"`
+}
+
+// lists 获取列表类型的规则名到字段的映射。
+func (r *ModuleRules) lists() map[string]*[]*Item {
+	return map[string]*[]*Item{
+		"PublicDependencyModuleNames":   &r.PublicDependencyModuleNames,
+		"PrivateDependencyModuleNames":  &r.PrivateDependencyModuleNames,
+		"DynamicallyLoadedModuleNames":  &r.DynamicallyLoadedModuleNames,
+		"PublicIncludePathModuleNames":  &r.PublicIncludePathModuleNames,
+		"PrivateIncludePathModuleNames": &r.PrivateIncludePathModuleNames,
+		"PublicIncludePaths":            &r.PublicIncludePaths,
+		"PrivateIncludePaths":           &r.PrivateIncludePaths,
+		"PublicSystemIncludePaths":      &r.PublicSystemIncludePaths,
+		"PublicDefinitions":             &r.PublicDefinitions,
+		"PrivateDefinitions":            &r.PrivateDefinitions,
+	}
+}
+
+// Flag 获取指定名字的 bool 规则的最后一次赋值，没有赋值时返回 nil。
+func (r *ModuleRules) Flag(name string) *Flag {
+	for i := len(r.Flags) - 1; i >= 0; i-- {
+		if r.Flags[i].Name == name {
+			return r.Flags[i]
+		}
+	}
+
+	return nil
+}
+
+// Parse 解析 *.Build.cs 的内容，找不到 ModuleRules 的子类时 module 名字为空。
+func Parse(content string) *ModuleRules {
+	p := newParser(content)
+	p.parseBlock()
+	return p.rules
+}
+
+// ParseFile 读取并解析 *.Build.cs 文件，找不到类名时使用文件名作为 module 名字。
+func ParseFile(path string) (*ModuleRules, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read module rules: %w", err)
+	}
+
+	rules := Parse(string(content))
+	if len(rules.Name) == 0 {
+		rules.Name = unreal.ModuleNameOfFile(path)
+	}
+
+	return rules, nil
+}
+
+// joinCondition 连接多个条件。
+func joinCondition(conds []string) string {
+	return strings.Join(conds, " && ")
+}
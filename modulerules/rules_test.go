@@ -0,0 +1,202 @@
+package modulerules
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestParseFile 测试解析 *.Build.cs 文件。
+func TestParseFile(t *testing.T) {
+	rules, err := ParseFile(filepath.Join("..", "testdata", "FakeRules", "FakeRules.Build.cs"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rules.Name != "FakeRules" {
+		t.Errorf("expect name FakeRules, actual %s", rules.Name)
+	}
+
+	if rules.PCHUsage == nil || rules.PCHUsage.Value != "UseExplicitOrSharedPCHs" || rules.PCHUsage.Conditional {
+		t.Errorf("unexpected PCHUsage %+v", rules.PCHUsage)
+	}
+
+	const (
+		win64   = "Target.Platform == UnrealTargetPlatform.Win64"
+		apple   = "Target.Platform == UnrealTargetPlatform.Mac || Target.Platform == UnrealTargetPlatform.IOS"
+		editor  = "Target.bBuildEditor"
+		foreach = `foreach (string Name in new string[] { "A", "B" })`
+	)
+
+	cases := []struct {
+		name   string
+		actual []*Item
+		expect []*Item
+	}{
+		{
+			name:   "public include paths",
+			actual: rules.PublicIncludePaths,
+			expect: []*Item{{Value: `Path.Combine(ModuleDirectory, "Public")`, Conditional: true, Expression: true}},
+		},
+		{
+			name:   "private include paths",
+			actual: rules.PrivateIncludePaths,
+			expect: []*Item{{Value: "FakeRules/Private"}},
+		},
+		{
+			name:   "public dependencies",
+			actual: rules.PublicDependencyModuleNames,
+			expect: []*Item{
+				{Value: "Core"},
+				{Value: "CoreUObject"},
+				{Value: "Engine"},
+				{Value: "D3D12RHI", Platforms: []string{"Win64"}, Condition: win64},
+			},
+		},
+		{
+			name:   "private dependencies",
+			actual: rules.PrivateDependencyModuleNames,
+			expect: []*Item{
+				{Value: "Slate"},
+				{Value: "SlateCore"},
+				{Value: "Json"},
+				{Value: "MetalRHI", Platforms: []string{"Mac", "IOS"}, ExcludedPlatforms: []string{"Win64"},
+					Condition: "!(" + win64 + ") && " + apple},
+				{Value: "VulkanRHI", ExcludedPlatforms: []string{"Win64", "Mac", "IOS"},
+					Condition: "!(" + win64 + ") && !(" + apple + ")"},
+				{Value: "UnrealEd", Condition: editor, Conditional: true},
+				{Value: "EditorStyle", Condition: editor, Conditional: true},
+			},
+		},
+		{
+			name:   "dynamically loaded",
+			actual: rules.DynamicallyLoadedModuleNames,
+			expect: []*Item{{Value: "ExtraModules", Conditional: true, Expression: true}},
+		},
+		{
+			name:   "public definitions",
+			actual: rules.PublicDefinitions,
+			expect: []*Item{
+				{Value: "WITH_FAKE=1"},
+				{Value: `"FAKE_PATH=" + ThirdPartyPath`, Conditional: true, Expression: true},
+				{Value: "FAKE_WINDOWS=1", Platforms: []string{"Win64"}, Condition: win64},
+			},
+		},
+		{
+			name:   "private definitions",
+			actual: rules.PrivateDefinitions,
+			expect: []*Item{
+				{Value: `FAKE_NAME="Fake"`},
+				{Value: "FAKE_LOOP", Condition: foreach, Conditional: true},
+			},
+		},
+	}
+
+	for i, c := range cases {
+		if !reflect.DeepEqual(c.actual, c.expect) {
+			t.Errorf("%d:%s: expect %v, actual %v", i, c.name, c.expect, c.actual)
+		}
+	}
+
+	expectFlags := []*Flag{
+		{Name: "bEnableExceptions", Item: Item{Value: "true"}},
+		{Name: "bUseRTTI", Item: Item{Value: "false"}},
+		{Name: "bLegacyPublicIncludePaths", Item: Item{Value: "Target.bBuildEditor", Conditional: true, Expression: true}},
+		{Name: "bUseUnity", Item: Item{Value: "false", ExcludedPlatforms: []string{"Android"},
+			Condition: "Target.Platform != UnrealTargetPlatform.Android"}},
+	}
+
+	if !reflect.DeepEqual(rules.Flags, expectFlags) {
+		for i, f := range rules.Flags {
+			t.Logf("%d: %+v", i, *f)
+		}
+		t.Errorf("unexpected flags")
+	}
+
+	if vulkan := rules.PrivateDependencyModuleNames[4]; vulkan.AppliesTo("Win64") || !vulkan.AppliesTo("Linux") {
+		t.Errorf("unexpected platforms of %s", vulkan)
+	}
+}
+
+// TestParseTolerant 测试无法识别的代码不会导致解析失败。
+func TestParseTolerant(t *testing.T) {
+	cases := []struct {
+		name    string
+		content string
+		expect  []string
+	}{
+		{
+			name:    "unclosed",
+			content: `public class A : ModuleRules { public A(ReadOnlyTargetRules Target) : base(Target) { PublicDependencyModuleNames.Add("Core"); if (`,
+			expect:  []string{"Core"},
+		},
+		{
+			name:    "unbalanced parens",
+			content: `public class A : ModuleRules { public A() { PublicDependencyModuleNames.Add("Core"; PublicDependencyModuleNames.Add("Engine")); } }`,
+			expect:  []string{"Core", "Engine"},
+		},
+		{
+			name:    "switch",
+			content: `public class A : ModuleRules { public A() { switch (Target.Platform) { case UnrealTargetPlatform.Win64: PublicDependencyModuleNames.Add("X"); break; default: break; } } }`,
+			expect:  []string{"X"},
+		},
+	}
+
+	for i, c := range cases {
+		rules := Parse(c.content)
+		var actual []string
+		for _, item := range rules.PublicDependencyModuleNames {
+			actual = append(actual, item.Value)
+		}
+
+		if !reflect.DeepEqual(actual, c.expect) {
+			t.Errorf("%d:%s: expect %v, actual %v", i, c.name, c.expect, actual)
+		}
+	}
+}
+
+// TestParseListAssignment 测试列表规则的赋值。
+func TestParseListAssignment(t *testing.T) {
+	const win64 = "Target.Platform == UnrealTargetPlatform.Win64"
+	cases := []struct {
+		name    string
+		content string
+		expect  []*Item
+	}{
+		{
+			name:    "initializer",
+			content: `PublicDependencyModuleNames.Add("Core"); PublicDependencyModuleNames = new string[] { "A", "B" };`,
+			expect:  []*Item{{Value: "A"}, {Value: "B"}},
+		},
+		{
+			name:    "variable",
+			content: `PublicDependencyModuleNames.Add("Core"); PublicDependencyModuleNames = SharedDeps;`,
+			expect:  []*Item{{Value: "SharedDeps", Conditional: true, Expression: true}},
+		},
+		{
+			name:    "constructor",
+			content: `PublicDependencyModuleNames = new List<string>(Other);`,
+			expect:  []*Item{{Value: "new List<string>(Other)", Conditional: true, Expression: true}},
+		},
+		{
+			name: "conditional",
+			content: `PublicDependencyModuleNames.Add("Core");
+				if (Target.Platform == UnrealTargetPlatform.Win64) { PublicDependencyModuleNames = new string[] { "A" }; }`,
+			expect: []*Item{{Value: "Core"}, {Value: "A", Platforms: []string{"Win64"}, Condition: win64}},
+		},
+		{
+			name: "conditional variable",
+			content: `PublicDependencyModuleNames.Add("Core");
+				if (Target.Platform == UnrealTargetPlatform.Win64) { PublicDependencyModuleNames = SharedDeps; }`,
+			expect: []*Item{{Value: "Core"},
+				{Value: "SharedDeps", Platforms: []string{"Win64"}, Condition: win64, Conditional: true, Expression: true}},
+		},
+	}
+
+	for i, c := range cases {
+		rules := Parse(`public class A : ModuleRules { public A(ReadOnlyTargetRules Target) : base(Target) { ` + c.content + ` } }`)
+		if !reflect.DeepEqual(rules.PublicDependencyModuleNames, c.expect) {
+			t.Errorf("%d:%s: expect %v, actual %v", i, c.name, c.expect, rules.PublicDependencyModuleNames)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package newcmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/zhiruili/urem/core"
+	"github.com/zhiruili/urem/osutil"
+	"github.com/zhiruili/urem/unreal"
+)
+
+// NewClangdCmd 是用于创建 .clangd 配置文件的子命令。
+type NewClangdCmd struct {
+	EngineIndex string `arg:"--engine-index" help:"external index file of the engine source generated by clangd-indexer, defaults to Engine/Intermediate/urem/engine.idx in the engine dir"`
+	ProjectFile string `arg:"positional,required"`
+}
+
+type clangdConfigContext struct {
+	CompilationDatabase string
+	EngineDir           string
+	EngineIndex         string
+}
+
+// yamlQuote 转义 YAML 单引号字符串中的内容。
+func yamlQuote(s string) string {
+	return strings.ReplaceAll(s, "'", "''")
+}
+
+// defaultEngineIndex 获取默认的引擎外部索引文件路径。
+func defaultEngineIndex(engineRoot string) string {
+	return filepath.Join(engineRoot, "Engine", "Intermediate", "urem", "engine.idx")
+}
+
+// newClangdConfigContext 创建 .clangd 模板使用的参数，engineIndex 为空时不生成引擎外部索引的配置。
+// 外部索引挂载到引擎根目录下的 Engine 目录，因为源码引擎的根目录下可能还有工程。clangd 配置中的路径都使用 / 作为分隔符。
+func newClangdConfigContext(engineRoot string, engineIndex string) *clangdConfigContext {
+	ctx := &clangdConfigContext{CompilationDatabase: "."}
+	if len(engineRoot) == 0 || len(engineIndex) == 0 {
+		return ctx
+	}
+
+	ctx.EngineDir = yamlQuote(filepath.ToSlash(filepath.Join(engineRoot, "Engine")))
+	ctx.EngineIndex = yamlQuote(filepath.ToSlash(engineIndex))
+	return ctx
+}
+
+// findEngineIndex 查找引擎的外部索引文件，文件不存在时返回空字符串并提示如何生成。
+// 生成索引需要编译整个引擎的 clang database，耗时很长，所以不会自动生成。
+func (cmd *NewClangdCmd) findEngineIndex(projectInfo *unreal.ProjectInfo, engineRoot string) string {
+	engineIndex := cmd.EngineIndex
+	if len(engineIndex) == 0 {
+		engineIndex = defaultEngineIndex(engineRoot)
+	}
+
+	if _, err := os.Stat(engineIndex); err != nil {
+		core.LogE("warning: engine index %s not found, skip the external index config. generate it with:\n"+
+			"    urem gen clang %s\n"+
+			"    clangd-indexer --executor=all-TUs --format=binary %s > %s\n"+
+			"then run this command again",
+			engineIndex, projectInfo.ProjectFilePath, filepath.Join(projectInfo.ProjectDir(), "compile_commands.json"), engineIndex)
+		return ""
+	}
+
+	return engineIndex
+}
+
+// renderClangdConfig 使用 ctx 渲染 .clangd 的模板。
+func renderClangdConfig(tmplText string, ctx *clangdConfigContext) ([]byte, error) {
+	tmpl, err := template.New("clangd").Parse(tmplText)
+	if err != nil {
+		return nil, fmt.Errorf("parse clangd file template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, ctx); err != nil {
+		return nil, fmt.Errorf("execute clangd file template: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (cmd *NewClangdCmd) generateClangdFile(projectFilePath string) error {
+	bs, err := core.Global.EmbedFs.ReadFile("resources/newclangd/clangd.tmpl")
+	if err != nil {
+		return fmt.Errorf("load clangd file template: %w", err)
+	}
+
+	projectInfo := &unreal.ProjectInfo{ProjectFilePath: projectFilePath}
+	engineDir := ""
+	engineIndex := ""
+	if info, err := unreal.FindProjectEngineInfo(projectInfo); err != nil {
+		core.LogE("find Unreal engine info: %s, skip the engine index config", err.Error())
+	} else {
+		engineDir = info.InstallPath
+		engineIndex = cmd.findEngineIndex(projectInfo, engineDir)
+	}
+
+	content, err := renderClangdConfig(string(bs), newClangdConfigContext(engineDir, engineIndex))
+	if err != nil {
+		return err
+	}
+
+	outFile := filepath.Join(projectInfo.ProjectDir(), ".clangd")
+	if _, err := os.Stat(outFile); err == nil {
+		if !core.GetUserBoolInput(fmt.Sprintf("%s already exists, overwrite it?", outFile)) {
+			return nil
+		}
+	}
+
+	core.LogD("write file to %s", outFile)
+	return os.WriteFile(outFile, content, 0644)
+}
+
+// Run 执行创建命令。
+func (cmd *NewClangdCmd) Run() error {
+	return osutil.DoInProjectRoot(cmd.ProjectFile, cmd.generateClangdFile)
+}
@@ -0,0 +1,94 @@
+package newcmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/zhiruili/urem/unreal"
+)
+
+// TestRenderClangdConfig 测试 .clangd 中引擎外部索引的配置。
+func TestRenderClangdConfig(t *testing.T) {
+	bs, err := os.ReadFile(filepath.Join("..", "resources", "newclangd", "clangd.tmpl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	cases := []struct {
+		name        string
+		engineRoot  string // 为空时表示找不到引擎
+		createIndex bool
+		expect      []string // 为空时不应该有外部索引的配置
+	}{
+		{
+			name:       "no engine",
+			engineRoot: "",
+		},
+		{
+			name:       "index missing",
+			engineRoot: filepath.Join(dir, "Missing"),
+		},
+		{
+			name:        "index found",
+			engineRoot:  filepath.Join(dir, "UE"),
+			createIndex: true,
+			expect: []string{
+				"    File: '" + filepath.ToSlash(filepath.Join(dir, "UE", "Engine", "Intermediate", "urem", "engine.idx")) + "'",
+				"    MountPoint: '" + filepath.ToSlash(filepath.Join(dir, "UE", "Engine")) + "'",
+			},
+		},
+		{
+			name:        "yaml quoting",
+			engineRoot:  filepath.Join(dir, "O'Neil UE"),
+			createIndex: true,
+			expect: []string{
+				"    File: '" + filepath.ToSlash(filepath.Join(dir, "O''Neil UE", "Engine", "Intermediate", "urem", "engine.idx")) + "'",
+				"    MountPoint: '" + filepath.ToSlash(filepath.Join(dir, "O''Neil UE", "Engine")) + "'",
+			},
+		},
+	}
+
+	projectInfo := &unreal.ProjectInfo{ProjectFilePath: filepath.Join(dir, "Game", "Game.uproject")}
+	for i, c := range cases {
+		engineIndex := ""
+		if len(c.engineRoot) != 0 {
+			if c.createIndex {
+				indexFile := defaultEngineIndex(c.engineRoot)
+				if err := os.MkdirAll(filepath.Dir(indexFile), os.ModePerm); err != nil {
+					t.Fatal(err)
+				}
+
+				if err := os.WriteFile(indexFile, nil, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			engineIndex = (&NewClangdCmd{}).findEngineIndex(projectInfo, c.engineRoot)
+		}
+
+		content, err := renderClangdConfig(string(bs), newClangdConfigContext(c.engineRoot, engineIndex))
+		if err != nil {
+			t.Fatalf("%d:%s: %s", i, c.name, err)
+		}
+
+		actual := string(content)
+		if !strings.Contains(actual, "  CompilationDatabase: .\n") || !strings.Contains(actual, "Index:\n  Background: Build\n") {
+			t.Errorf("%d:%s: missing compilation database or background index:\n%s", i, c.name, actual)
+		}
+
+		if len(c.expect) == 0 {
+			if strings.Contains(actual, "External:") {
+				t.Errorf("%d:%s: expect no external index:\n%s", i, c.name, actual)
+			}
+			continue
+		}
+
+		expect := "  External:\n" + strings.Join(c.expect, "\n") + "\n"
+		if !strings.HasSuffix(actual, expect) {
+			t.Errorf("%d:%s:\nexpect suffix:\n%s\n\nactual:\n%s", i, c.name, expect, actual)
+		}
+	}
+}
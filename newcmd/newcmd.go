@@ -10,6 +10,7 @@ type Cmd struct {
 	NewFormatCommand    *NewFormatCmd    `arg:"subcommand:fmt"`
 	NewIgnoreCommand    *NewIgnoreCmd    `arg:"subcommand:ig"`
 	NewAttributeCommand *NewAttributeCmd `arg:"subcommand:attr"`
+	NewClangdCommand    *NewClangdCmd    `arg:"subcommand:clangd"`
 }
 
 // Run 实现了 subCmd 的接口。
@@ -22,7 +23,9 @@ func (cmd *Cmd) Run() error {
 		return cmd.NewIgnoreCommand.Run()
 	} else if cmd.NewAttributeCommand != nil {
 		return cmd.NewAttributeCommand.Run()
+	} else if cmd.NewClangdCommand != nil {
+		return cmd.NewClangdCommand.Run()
 	}
 
-	return fmt.Errorf("missing target: mod/fmt/ig/attr")
+	return fmt.Errorf("missing target: mod/fmt/ig/attr/clangd")
 }
@@ -6,11 +6,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"strings"
 	"text/template"
 	"unicode"
 
 	"github.com/zhiruili/urem/core"
+	"github.com/zhiruili/urem/descriptor"
 	"github.com/zhiruili/urem/gencmd"
 	"github.com/zhiruili/urem/infocmd"
 	"github.com/zhiruili/urem/osutil"
@@ -62,39 +62,26 @@ func (cmd *NewModCmd) generateFile(info *genFileInfo, modulePath string, fs *emb
 	return filePath, nil
 }
 
-func formatProjectJsonText(orignalJson string, moduleName string, moduleType string, loadingPhase string) string {
-	ctx := projectJsonFormatContext{
-		ModuleName:   moduleName,
-		ModuleType:   moduleType,
-		LoadingPhase: loadingPhase,
+// addModuleToDescriptor 将新的 module 添加到 .uproject 或 .uplugin 文件内容的 Modules 列表最前面，
+// 文件中原有的字段、字段顺序和缩进保持不变。
+func addModuleToDescriptor(content []byte, isPlugin bool, module *descriptor.ModuleDescriptor) ([]byte, error) {
+	if isPlugin {
+		d, err := descriptor.ParsePlugin(content)
+		if err != nil {
+			return nil, err
+		}
+
+		d.Modules = append([]*descriptor.ModuleDescriptor{module}, d.Modules...)
+		return d.Marshal()
 	}
 
-	moduleTagIdx := strings.Index(orignalJson, "\"Modules\"")
-	if moduleTagIdx < 0 {
-		closeParansIdx := strings.LastIndex(orignalJson, "}")
-		if closeParansIdx < 0 {
-			return orignalJson
-		}
-		ctx.FormatPrefix = strings.TrimRight(orignalJson[:closeParansIdx], " \t\r\n") + ",\n\t\"Modules\": ["
-		ctx.FormatSuffix = "]\n" + orignalJson[closeParansIdx:]
-		ctx.HasOtherModules = false
-	} else {
-		afterModelTag := orignalJson[moduleTagIdx:]
-		modelOpenBracketIdx := strings.Index(afterModelTag, "[")
-		if modelOpenBracketIdx < 0 {
-			return orignalJson
-		}
-		afterOpenBracketIdx := moduleTagIdx + modelOpenBracketIdx + 1
-		ctx.FormatPrefix = orignalJson[:afterOpenBracketIdx]
-		ctx.FormatSuffix = strings.TrimLeft(orignalJson[afterOpenBracketIdx:], " \t\r\n")
-		endBracketIdx := strings.Index(ctx.FormatSuffix, "]")
-		ctx.HasOtherModules = strings.TrimSpace(ctx.FormatSuffix[:endBracketIdx]) != ""
+	d, err := descriptor.ParseProject(content)
+	if err != nil {
+		return nil, err
 	}
 
-	tmplEngine := template.Must(template.New("ProjectJSON").Parse(projectJsonTmpl))
-	out := new(bytes.Buffer)
-	tmplEngine.Execute(out, &ctx)
-	return out.String()
+	d.Modules = append([]*descriptor.ModuleDescriptor{module}, d.Modules...)
+	return d.Marshal()
 }
 
 func (cmd *NewModCmd) updateProjectJson(modulePath string) error {
@@ -112,8 +99,19 @@ func (cmd *NewModCmd) updateProjectJson(modulePath string) error {
 		return fmt.Errorf("read file %s", filePath)
 	}
 
-	updated := formatProjectJsonText(string(content), cmd.ModuleName, cmd.ModuleType, cmd.LoadingPhase)
-	if err := os.WriteFile(filePath, []byte(updated), 0644); err != nil {
+	module := &descriptor.ModuleDescriptor{
+		Name:         cmd.ModuleName,
+		Type:         cmd.ModuleType,
+		LoadingPhase: cmd.LoadingPhase,
+	}
+
+	isPlugin := filepath.Ext(filePath) == ".uplugin"
+	updated, err := addModuleToDescriptor(content, isPlugin, module)
+	if err != nil {
+		return fmt.Errorf("add module to %s: %w", filePath, err)
+	}
+
+	if err := os.WriteFile(filePath, updated, 0644); err != nil {
 		return fmt.Errorf("write file %s", filePath)
 	}
 
@@ -2,10 +2,12 @@ package newcmd
 
 import (
 	"testing"
+
+	"github.com/zhiruili/urem/descriptor"
 )
 
-// TestFormatProjectJsonText 测试 formatProjectJsonText 函数。
-func TestFormatProjectJsonText(t *testing.T) {
+// TestAddModuleToDescriptor 测试 addModuleToDescriptor 函数。
+func TestAddModuleToDescriptor(t *testing.T) {
 	cases := []struct {
 		name   string
 		json   string
@@ -81,8 +83,14 @@ func TestFormatProjectJsonText(t *testing.T) {
 	}
 
 	for i, c := range cases {
-		actual := formatProjectJsonText(c.json, "NewModule", "Runtime", "Default")
-		if c.expect != actual {
+		module := &descriptor.ModuleDescriptor{Name: "NewModule", Type: "Runtime", LoadingPhase: "Default"}
+		actual, err := addModuleToDescriptor([]byte(c.json), false, module)
+		if err != nil {
+			t.Errorf("%d:%s: unexpected error: %s", i, c.name, err)
+			continue
+		}
+
+		if c.expect != string(actual) {
 			t.Errorf("%d:%s:\nexpect:\n%s\n\nactual:\n%s", i, c.name, c.expect, actual)
 		}
 	}
@@ -33,21 +33,3 @@ var genFileInfos = []*genFileInfo{
 		"Private/{{.ModuleName}}Module.cpp",
 	},
 }
-
-const projectJsonTmpl = `{{.FormatPrefix}}
-		{
-			"Name": "{{.ModuleName}}",
-			"Type": "{{.ModuleType}}",
-			"LoadingPhase": "{{.LoadingPhase}}"
-		}{{if .HasOtherModules}},
-		{{else}}
-	{{end}}{{.FormatSuffix}}`
-
-type projectJsonFormatContext struct {
-	ModuleName      string
-	HasOtherModules bool
-	ModuleType      string
-	LoadingPhase    string
-	FormatPrefix    string
-	FormatSuffix    string
-}
@@ -0,0 +1,117 @@
+package switchcmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+
+	"github.com/zhiruili/urem/core"
+	"github.com/zhiruili/urem/gencmd"
+	"github.com/zhiruili/urem/osutil"
+	"github.com/zhiruili/urem/unreal"
+)
+
+// Cmd 是用于切换工程所用引擎的命令。
+type Cmd struct {
+	NoGen       bool   `arg:"--no-gen" help:"don't regenerate project files and clang database after switching"`
+	ProjectFile string `arg:"positional,required"`
+	Engine      string `arg:"positional" help:"version, identifier or root dir of the target engine, choose interactively if not set"`
+}
+
+// findTargetEngine 根据版本号、GUID 或者引擎目录在候选引擎中查找目标引擎。
+func findTargetEngine(infos []*unreal.EngineInfo, target string) (*unreal.EngineInfo, error) {
+	if info, err := unreal.FindEngineInfo(target); err == nil {
+		return info, nil
+	}
+
+	if absTarget, err := filepath.Abs(target); err == nil {
+		for _, info := range infos {
+			if filepath.Clean(info.InstallPath) == absTarget {
+				return info, nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("engine '%s' not found, run 'urem engine register' to register a source build", target)
+}
+
+// chooseEngine 列出所有候选引擎并让用户选择。
+func chooseEngine(infos []*unreal.EngineInfo) (*unreal.EngineInfo, error) {
+	if core.Global.Quite {
+		return nil, core.IllegalArgErrorf("Engine", "must be set when running with --quite")
+	}
+
+	var choices []string
+	for i, info := range infos {
+		choice := strconv.Itoa(i + 1)
+		choices = append(choices, choice)
+		fmt.Printf("%s) %s %s (%s)\n", choice, info.RealVersion(), info.Version, info.InstallPath)
+	}
+
+	input := core.GetUserInput("Choose the engine", choices...)
+	idx, err := strconv.Atoi(input)
+	if err != nil {
+		return nil, fmt.Errorf("user cancel")
+	}
+
+	return infos[idx-1], nil
+}
+
+func (cmd *Cmd) switchEngine(projectFilePath string) error {
+	projectInfo := &unreal.ProjectInfo{ProjectFilePath: projectFilePath}
+	oldAssociation, err := projectInfo.GetEngineAssociation()
+	if err != nil {
+		return fmt.Errorf("get Unreal engine association: %w", err)
+	}
+
+	infos, err := unreal.FindAllEngineInfos()
+	if err != nil {
+		return fmt.Errorf("find Unreal engine info: %w", err)
+	}
+
+	var target *unreal.EngineInfo
+	if len(cmd.Engine) != 0 {
+		target, err = findTargetEngine(infos, cmd.Engine)
+	} else {
+		target, err = chooseEngine(infos)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if target.Version == oldAssociation {
+		core.LogI("project already associated with %s", oldAssociation)
+		return nil
+	}
+
+	hint := fmt.Sprintf("Change EngineAssociation of %s from '%s' to '%s' (%s %s), continue?",
+		projectInfo.ProjectFileName(), oldAssociation, target.Version, target.RealVersion(), target.InstallPath)
+	if !core.GetUserBoolInput(hint) {
+		return fmt.Errorf("user cancel")
+	}
+
+	if err := projectInfo.UpdateEngineAssociation(target.Version); err != nil {
+		return fmt.Errorf("update engine association: %w", err)
+	}
+
+	core.LogI("switch engine of %s to %s", projectInfo.ProjectFileName(), target.Version)
+	if cmd.NoGen {
+		return nil
+	}
+
+	if err := (&gencmd.GenVsCmd{ProjectFile: projectFilePath}).Run(); err != nil {
+		return fmt.Errorf("generate project files: %w", err)
+	}
+
+	if err := (&gencmd.GenClangCmd{ProjectFile: projectFilePath}).Run(); err != nil {
+		return fmt.Errorf("generate clang database: %w", err)
+	}
+
+	return nil
+}
+
+// Run 执行切换引擎的操作。
+func (cmd *Cmd) Run() error {
+	return osutil.DoInProjectRoot(cmd.ProjectFile, cmd.switchEngine)
+}
@@ -0,0 +1,76 @@
+// Copyright Fake. All Rights Reserved.
+
+using System.IO;
+using UnrealBuildTool;
+
+public class FakeRules : ModuleRules
+{
+	private string ThirdPartyPath
+	{
+		get { return Path.GetFullPath(Path.Combine(ModuleDirectory, "../ThirdParty/")); }
+	}
+
+	public FakeRules(ReadOnlyTargetRules Target) : base(Target)
+	{
+		PCHUsage = ModuleRules.PCHUsageMode.UseExplicitOrSharedPCHs;
+		bEnableExceptions = true;
+		bUseRTTI = false;
+		bLegacyPublicIncludePaths = Target.bBuildEditor;
+
+		PublicIncludePaths.AddRange(
+			new string[] {
+				// ... add public include paths required here ...
+				Path.Combine(ModuleDirectory, "Public"),
+			}
+			);
+
+		PrivateIncludePaths.Add("FakeRules/Private");
+
+		PublicDependencyModuleNames.AddRange(
+			new string[]
+			{
+				"Core", /* inline comment, "NotAModule" */
+				"CoreUObject",
+				"Engine",
+			}
+			);
+
+		PrivateDependencyModuleNames.AddRange(new string[] { "Slate", "SlateCore" });
+		this.PrivateDependencyModuleNames.Add("Json");
+
+		DynamicallyLoadedModuleNames.AddRange(ExtraModules);
+
+		PublicDefinitions.Add("WITH_FAKE=1");
+		PublicDefinitions.Add("FAKE_PATH=" + ThirdPartyPath);
+		PrivateDefinitions.Add(@"FAKE_NAME=""Fake""");
+
+		if (Target.Platform == UnrealTargetPlatform.Win64)
+		{
+			PublicDependencyModuleNames.Add("D3D12RHI");
+			PublicDefinitions.Add("FAKE_WINDOWS=1");
+		}
+		else if (Target.Platform == UnrealTargetPlatform.Mac || Target.Platform == UnrealTargetPlatform.IOS)
+		{
+			PrivateDependencyModuleNames.Add("MetalRHI");
+		}
+		else
+		{
+			PrivateDependencyModuleNames.Add("VulkanRHI");
+		}
+
+		if (Target.Platform != UnrealTargetPlatform.Android)
+			bUseUnity = false;
+
+		if (Target.bBuildEditor)
+		{
+			PrivateDependencyModuleNames.AddRange(new string[] { "UnrealEd", "EditorStyle" });
+		}
+
+		foreach (string Name in new string[] { "A", "B" })
+		{
+			PrivateDefinitions.Add("FAKE_LOOP");
+		}
+	}
+
+	private string[] ExtraModules = new string[] { "Loaded" };
+}
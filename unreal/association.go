@@ -0,0 +1,128 @@
+package unreal
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/zhiruili/urem/core"
+	"github.com/zhiruili/urem/descriptor"
+)
+
+// normalizeGuid 将各种格式的 GUID 统一成 32 位大写十六进制字符串，如果不是 GUID 则返回空字符串。
+// UE 中 FGuid::Parse 支持带或不带花括号、带或不带连字符的格式，这里同样都做兼容。
+func normalizeGuid(s string) string {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "{") && strings.HasSuffix(s, "}") {
+		s = s[1 : len(s)-1]
+	}
+
+	s = strings.ReplaceAll(s, "-", "")
+	if len(s) != 32 {
+		return ""
+	}
+
+	for _, c := range s {
+		isHex := (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+		if !isHex {
+			return ""
+		}
+	}
+
+	return strings.ToUpper(s)
+}
+
+// IsGuidAssociation 检查 EngineAssociation 是否是源码引擎使用的 GUID 格式。
+func IsGuidAssociation(association string) bool {
+	return normalizeGuid(association) != ""
+}
+
+// matchAssociation 检查引擎信息是否和 EngineAssociation 匹配。
+func matchAssociation(info *EngineInfo, association string) bool {
+	if info.Version == association {
+		return true
+	}
+
+	guid := normalizeGuid(association)
+	return guid != "" && guid == normalizeGuid(info.Version)
+}
+
+// findEngineInParentDirs 自底向上查找包含 Engine/Build/Build.version 的目录，用于 EngineAssociation 为空的工程。
+// 参考 UE 的实现：
+// FDesktopPlatformBase::GetEngineIdentifierForProject
+func findEngineInParentDirs(dir string) (string, bool) {
+	dir = filepath.Clean(dir)
+	for {
+		if IsEngineDir(dir) {
+			return dir, true
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+
+		dir = parent
+	}
+}
+
+// ResolveEngineAssociation 根据 EngineAssociation 查找对应的引擎，支持以下几种格式：
+//   - launcher 安装的引擎使用的版本号，如 5.3
+//   - 源码引擎注册时使用的 GUID
+//   - 空字符串，此时从工程目录开始向上查找引擎目录
+func ResolveEngineAssociation(association string, projectDir string) (*EngineInfo, error) {
+	if len(association) == 0 {
+		engineDir, ok := findEngineInParentDirs(projectDir)
+		if !ok {
+			return nil, fmt.Errorf("empty EngineAssociation and no engine found in parent dirs of %s", projectDir)
+		}
+
+		core.LogD("find engine in parent dir %s", engineDir)
+		info := &EngineInfo{InstallPath: engineDir}
+		fillBuildVersion(info)
+		return info, nil
+	}
+
+	return FindEngineInfo(association)
+}
+
+// GetEngineAssociation 获取工程文件中的 EngineAssociation 字段，可能为空。
+func (pi *ProjectInfo) GetEngineAssociation() (string, error) {
+	d, err := descriptor.LoadProject(pi.ProjectFilePath)
+	if err != nil {
+		return "", err
+	}
+
+	return d.EngineAssociation, nil
+}
+
+// FindProjectEngineInfo 查找工程使用的引擎信息。
+// 依次检查命令行参数 --engine、环境变量 UREM_ENGINE_ROOT、工程配置文件中的 EngineRoot，
+// 都没有指定时再根据工程的 EngineAssociation 查找。
+func FindProjectEngineInfo(projectInfo *ProjectInfo) (*EngineInfo, error) {
+	info, err := findOverrideEngineInfo(projectInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	if info != nil {
+		core.LogD("use engine %s from %s", info.InstallPath, info.Source)
+		return info, nil
+	}
+
+	association, err := projectInfo.GetEngineAssociation()
+	if err != nil {
+		return nil, fmt.Errorf("get engine association: %w", err)
+	}
+
+	core.LogD("get engine association: '%s'", association)
+	info, err = ResolveEngineAssociation(association, projectInfo.ProjectDir())
+	if err != nil {
+		return nil, err
+	}
+
+	// 返回一份拷贝，避免修改 FindAllEngineInfos 结果中的数据
+	resolved := *info
+	resolved.Source = EngineSourceAssociation
+	return &resolved, nil
+}
@@ -0,0 +1,51 @@
+package unreal
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// TestNormalizeGuid 测试 normalizeGuid 函数。
+func TestNormalizeGuid(t *testing.T) {
+	cases := []struct {
+		name   string
+		guid   string
+		expect string
+	}{
+		{"braces with hyphens", "{4a1d5c2e-6f5b-4a34-8d5b-1b2c3d4e5f60}", "4A1D5C2E6F5B4A348D5B1B2C3D4E5F60"},
+		{"hyphens only", "4A1D5C2E-6F5B-4A34-8D5B-1B2C3D4E5F60", "4A1D5C2E6F5B4A348D5B1B2C3D4E5F60"},
+		{"digits only", "4A1D5C2E6F5B4A348D5B1B2C3D4E5F60", "4A1D5C2E6F5B4A348D5B1B2C3D4E5F60"},
+		{"launcher version", "5.3", ""},
+		{"empty", "", ""},
+		{"illegal char", "{4A1D5C2E-6F5B-4A34-8D5B-1B2C3D4E5F6X}", ""},
+	}
+
+	for i, c := range cases {
+		actual := normalizeGuid(c.guid)
+		if actual != c.expect {
+			t.Errorf("%d:%s: expect '%s', got '%s'", i, c.name, c.expect, actual)
+		}
+	}
+}
+
+// TestResolveForeignEngineAssociation 测试 EngineAssociation 为空时从上层目录查找引擎。
+func TestResolveForeignEngineAssociation(t *testing.T) {
+	projectDir := filepath.Join("..", "testdata", "FakeEngine", "FakeGame")
+	info, err := ResolveEngineAssociation("", projectDir)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expectPath := filepath.Join("..", "testdata", "FakeEngine")
+	if info.InstallPath != expectPath {
+		t.Errorf("expect install path %s, got %s", expectPath, info.InstallPath)
+	}
+
+	if info.RealVersion() != "5.3.2" {
+		t.Errorf("expect version 5.3.2, got %s", info.RealVersion())
+	}
+
+	if _, err := ResolveEngineAssociation("", filepath.Join("..", "testdata", "FakePlugin")); err == nil {
+		t.Errorf("expect error when no engine in parent dirs")
+	}
+}
@@ -0,0 +1,75 @@
+package unreal
+
+import (
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/zhiruili/urem/core"
+)
+
+// buildInputSkipDirs 是查找构建输入文件时跳过的目录，其中不会有影响 UBT 的文件，但可能有大量其他文件。
+var buildInputSkipDirs = []string{"Binaries", "Intermediate", "Saved", "Content", "Resources", "DerivedDataCache"}
+
+// IsBuildInputFile 检查文件是否会影响 UBT 生成的工程文件，即 *.Build.cs、*.Target.cs、*.uproject 和 *.uplugin，不区分大小写。
+func IsBuildInputFile(name string) bool {
+	for _, suffix := range []string{buildFileSuffix, targetFileSuffix, ".uproject", pluginFileSuffix} {
+		if hasSuffixFold(name, suffix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// WalkProjectFiles 遍历工程 Source 和 Plugins 目录下的所有文件，跳过隐藏目录和 Binaries、Intermediate 等生成的目录。
+func (pi *ProjectInfo) WalkProjectFiles(fn func(path string, d fs.DirEntry) error) error {
+	for _, root := range []string{pi.ProjectSourceDir(), pi.ProjectPluginsDir()} {
+		err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if p == root {
+					// 目录不存在时跳过
+					return filepath.SkipDir
+				}
+				return err
+			}
+
+			if d.IsDir() {
+				name := d.Name()
+				if p != root && (strings.HasPrefix(name, ".") || core.StrContains(buildInputSkipDirs, name)) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+
+			return fn(p, d)
+		})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// FindBuildInputFiles 查找工程中所有影响 UBT 生成结果的文件，包括工程文件，以及 Source 和 Plugins 目录下的
+// *.Build.cs、*.Target.cs 和 *.uplugin 文件，返回排序后的绝对路径。
+func (pi *ProjectInfo) FindBuildInputFiles() ([]string, error) {
+	files := []string{pi.ProjectFilePath}
+	err := pi.WalkProjectFiles(func(p string, d fs.DirEntry) error {
+		if IsBuildInputFile(d.Name()) {
+			files = append(files, p)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(files)
+	return files, nil
+}
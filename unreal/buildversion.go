@@ -0,0 +1,57 @@
+package unreal
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// BuildVersion 对应引擎目录下 Engine/Build/Build.version 文件的内容。
+type BuildVersion struct {
+	MajorVersion         int
+	MinorVersion         int
+	PatchVersion         int
+	Changelist           int
+	CompatibleChangelist int
+	IsLicenseeVersion    int
+	IsPromotedBuild      int
+	BranchName           string
+	BuildId              string
+}
+
+// String 返回 Major.Minor.Patch 格式的版本号。
+func (v *BuildVersion) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.MajorVersion, v.MinorVersion, v.PatchVersion)
+}
+
+// MajorMinor 返回 Major.Minor 格式的版本号，和 launcher 安装的引擎使用的 EngineAssociation 格式一致。
+func (v *BuildVersion) MajorMinor() string {
+	return fmt.Sprintf("%d.%d", v.MajorVersion, v.MinorVersion)
+}
+
+// BuildVersionPath 获取引擎目录下 Build.version 文件的路径。
+func BuildVersionPath(engineDir string) string {
+	return filepath.Join(engineDir, "Engine", "Build", "Build.version")
+}
+
+// IsEngineDir 检查给定目录是否是一个引擎的根目录。
+func IsEngineDir(dir string) bool {
+	stat, err := os.Stat(BuildVersionPath(dir))
+	return err == nil && !stat.IsDir()
+}
+
+// ReadBuildVersion 读取引擎目录下的 Build.version 文件。
+func ReadBuildVersion(engineDir string) (*BuildVersion, error) {
+	content, err := os.ReadFile(BuildVersionPath(engineDir))
+	if err != nil {
+		return nil, fmt.Errorf("open build version file: %w", err)
+	}
+
+	var ver BuildVersion
+	if err := json.Unmarshal(content, &ver); err != nil {
+		return nil, fmt.Errorf("unmarshal build version file: %w", err)
+	}
+
+	return &ver, nil
+}
@@ -0,0 +1,65 @@
+package unreal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/zhiruili/urem/core"
+	"github.com/zhiruili/urem/osutil"
+)
+
+// clangDbFileName 是 UBT GenerateClangDatabase 模式生成的文件名。
+const clangDbFileName = "compile_commands.json"
+
+// ProjectUremIntermediateDir 获取 urem 存放中间文件的目录，即工程的 Intermediate/urem 目录。
+func (pi *ProjectInfo) ProjectUremIntermediateDir() string {
+	return filepath.Join(pi.ProjectDir(), "Intermediate", "urem")
+}
+
+// ProjectGeneratedClangDbPath 获取通过 GenerateClangDatabase 模式生成的 clang database 的路径。
+func (pi *ProjectInfo) ProjectGeneratedClangDbPath() string {
+	return filepath.Join(pi.ProjectUremIntermediateDir(), clangDbFileName)
+}
+
+// SupportsClangDatabaseMode 检查引擎的 UBT 是否支持 -mode=GenerateClangDatabase，UE 4.26 开始支持。
+func SupportsClangDatabaseMode(ver *BuildVersion) bool {
+	return ver != nil && (ver.MajorVersion > 4 || (ver.MajorVersion == 4 && ver.MinorVersion >= 26))
+}
+
+// supportsClangDatabaseOutputDir 检查 GenerateClangDatabase 模式是否支持 -OutputDir 参数，
+// 不支持时 UBT 会将文件写到引擎的根目录下。
+func supportsClangDatabaseOutputDir(ver *BuildVersion) bool {
+	return ver != nil && (ver.MajorVersion > 5 || (ver.MajorVersion == 5 && ver.MinorVersion >= 1))
+}
+
+// ExecuteUbtGenClangDatabase 通过 UBT 的 GenerateClangDatabase 模式为指定 target 生成 clang database，
+// 返回生成的文件路径。参考 UBT 的实现：
+// GenerateClangDatabase.cs
+func ExecuteUbtGenClangDatabase(engine *EngineInfo, projectInfo *ProjectInfo, target string, platform string, configuration string) (string, error) {
+	outDir := projectInfo.ProjectUremIntermediateDir()
+	if err := os.MkdirAll(outDir, os.ModePerm); err != nil {
+		return "", fmt.Errorf("create dir %s: %w", outDir, err)
+	}
+
+	args := []string{"-mode=GenerateClangDatabase", "-project=" + projectInfo.ProjectFilePath, target, platform, configuration}
+	withOutputDir := supportsClangDatabaseOutputDir(engine.BuildVersion)
+	if withOutputDir {
+		args = append(args, "-OutputDir="+outDir)
+	}
+
+	if err := ExecuteUbt(engine.InstallPath, projectInfo, args...); err != nil {
+		return "", fmt.Errorf("execute UBT: %w", err)
+	}
+
+	dbPath := projectInfo.ProjectGeneratedClangDbPath()
+	if !withOutputDir {
+		engineDbPath := filepath.Join(engine.InstallPath, clangDbFileName)
+		core.LogD("copy clang database from %s to %s", engineDbPath, dbPath)
+		if err := osutil.CopyFile(engineDbPath, dbPath); err != nil {
+			return "", fmt.Errorf("copy clang database from engine dir: %w", err)
+		}
+	}
+
+	return dbPath, nil
+}
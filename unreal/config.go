@@ -0,0 +1,43 @@
+package unreal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ProjectConfigFileName 是放在 .uproject 文件旁边的 urem 工程配置文件名。
+const ProjectConfigFileName = ".urem"
+
+// ProjectConfig 是 urem 的工程配置，以 JSON 格式保存。
+type ProjectConfig struct {
+	EngineRoot     string            `json:",omitempty"` // 工程使用的引擎根目录，相对路径基于工程目录
+	ClangFlagStyle string            `json:",omitempty"` // compile_commands.json 使用的参数风格，auto/msvc/gnu
+	ClangFlagRules map[string]string `json:",omitempty"` // 参数前缀到替换内容的映射，替换内容为空时删除该参数
+}
+
+// ProjectConfigPath 获取工程配置文件的路径。
+func (pi *ProjectInfo) ProjectConfigPath() string {
+	return filepath.Join(pi.ProjectDir(), ProjectConfigFileName)
+}
+
+// LoadConfig 读取工程配置，配置文件不存在时返回空配置。
+func (pi *ProjectInfo) LoadConfig() (*ProjectConfig, error) {
+	var config ProjectConfig
+	content, err := os.ReadFile(pi.ProjectConfigPath())
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return &config, nil
+		}
+
+		return nil, fmt.Errorf("open project config file: %w", err)
+	}
+
+	if err := json.Unmarshal(content, &config); err != nil {
+		return nil, fmt.Errorf("unmarshal project config file %s: %w", pi.ProjectConfigPath(), err)
+	}
+
+	return &config, nil
+}
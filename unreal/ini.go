@@ -0,0 +1,212 @@
+package unreal
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// iniFile 是 UE 风格 ini 文件的简单表示，按文件中出现的顺序保存 section 和 key。
+type iniFile struct {
+	sections []*iniSection
+}
+
+type iniSection struct {
+	name    string
+	entries []*iniEntry
+}
+
+type iniEntry struct {
+	key   string
+	value string
+}
+
+// parseIni 解析 ini 文件内容，忽略空行和以 ; 或 # 开头的注释。
+func parseIni(r io.Reader) (*iniFile, error) {
+	file := &iniFile{}
+	var cur *iniSection
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || strings.HasPrefix(line, ";") || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			cur = &iniSection{name: strings.TrimSpace(line[1 : len(line)-1])}
+			file.sections = append(file.sections, cur)
+			continue
+		}
+
+		if cur == nil {
+			// 不属于任何 section 的内容，UE 也会直接忽略
+			continue
+		}
+
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			continue
+		}
+
+		cur.entries = append(cur.entries, &iniEntry{
+			key:   strings.TrimSpace(key),
+			value: strings.Trim(strings.TrimSpace(value), "\""),
+		})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return file, nil
+}
+
+// section 查找指定名字的 section，和 UE 一样不区分大小写，找不到时返回 nil。
+func (f *iniFile) section(name string) *iniSection {
+	for _, s := range f.sections {
+		if strings.EqualFold(s.name, name) {
+			return s
+		}
+	}
+
+	return nil
+}
+
+// get 获取 key 对应的值，不区分大小写。
+func (s *iniSection) get(key string) (string, bool) {
+	for _, e := range s.entries {
+		if strings.EqualFold(e.key, key) {
+			return e.value, true
+		}
+	}
+
+	return "", false
+}
+
+// iniEditor 逐行修改 ini 文件，只改动需要修改的行，其余内容（注释、空行、引号、换行符等）保持原样。
+type iniEditor struct {
+	lines   []string
+	newline string
+}
+
+// newIniEditor 根据 ini 文件的内容创建 iniEditor。
+func newIniEditor(content string) *iniEditor {
+	e := &iniEditor{newline: "\n"}
+	if strings.Contains(content, "\r\n") {
+		e.newline = "\r\n"
+		content = strings.ReplaceAll(content, "\r\n", "\n")
+	}
+
+	if len(content) != 0 {
+		e.lines = strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+	}
+
+	return e
+}
+
+// iniSectionName 获取 section 头所在行的 section 名字，不是 section 头时返回 false。
+func iniSectionName(line string) (string, bool) {
+	line = strings.TrimSpace(line)
+	if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+		return strings.TrimSpace(line[1 : len(line)-1]), true
+	}
+
+	return "", false
+}
+
+// iniKey 获取 key = value 所在行的 key，注释和没有 = 的行返回 false。
+func iniKey(line string) (string, bool) {
+	line = strings.TrimSpace(line)
+	if strings.HasPrefix(line, ";") || strings.HasPrefix(line, "#") {
+		return "", false
+	}
+
+	key, _, found := strings.Cut(line, "=")
+	return strings.TrimSpace(key), found
+}
+
+// sectionRange 获取 section 的内容所在的行 [begin, end)，begin 是 section 头的下一行，找不到时返回 -1。
+func (e *iniEditor) sectionRange(name string) (int, int) {
+	begin := -1
+	for i, line := range e.lines {
+		section, ok := iniSectionName(line)
+		if !ok {
+			continue
+		}
+
+		if begin >= 0 {
+			return begin, i
+		}
+
+		if strings.EqualFold(section, name) {
+			begin = i + 1
+		}
+	}
+
+	return begin, len(e.lines)
+}
+
+// findKey 在 section 中查找第一个 key 满足 match 的行，找不到时返回 -1。
+func (e *iniEditor) findKey(section string, match func(key string) bool) int {
+	begin, end := e.sectionRange(section)
+	if begin < 0 {
+		return -1
+	}
+
+	for i := begin; i < end; i++ {
+		if key, ok := iniKey(e.lines[i]); ok && match(key) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// set 将 section 中第一个 key 满足 match 的行替换为 key=value，找不到时追加到 section 末尾，
+// section 不存在时在文件末尾创建。
+func (e *iniEditor) set(section string, match func(key string) bool, key string, value string) {
+	line := key + "=" + value
+	if i := e.findKey(section, match); i >= 0 {
+		e.lines[i] = line
+		return
+	}
+
+	begin, end := e.sectionRange(section)
+	if begin < 0 {
+		if len(e.lines) != 0 && len(strings.TrimSpace(e.lines[len(e.lines)-1])) != 0 {
+			e.lines = append(e.lines, "")
+		}
+
+		e.lines = append(e.lines, "["+section+"]", line)
+		return
+	}
+
+	// 插入到 section 中最后一个非空行之后，保留 section 之间的空行
+	at := end
+	for at > begin && len(strings.TrimSpace(e.lines[at-1])) == 0 {
+		at--
+	}
+
+	e.lines = append(e.lines[:at], append([]string{line}, e.lines[at:]...)...)
+}
+
+// remove 删除 section 中所有 key 满足 match 的行，返回是否有删除。
+func (e *iniEditor) remove(section string, match func(key string) bool) bool {
+	removed := false
+	for i := e.findKey(section, match); i >= 0; i = e.findKey(section, match) {
+		e.lines = append(e.lines[:i], e.lines[i+1:]...)
+		removed = true
+	}
+
+	return removed
+}
+
+// String 获取修改后的 ini 文件内容。
+func (e *iniEditor) String() string {
+	if len(e.lines) == 0 {
+		return ""
+	}
+
+	return strings.Join(e.lines, e.newline) + e.newline
+}
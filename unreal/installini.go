@@ -0,0 +1,111 @@
+package unreal
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/zhiruili/urem/core"
+	"github.com/zhiruili/urem/osutil"
+)
+
+// installationsSection 是 Install.ini 中记录引擎安装信息的 section 名。
+const installationsSection = "Installations"
+
+// InstallIniPath 获取当前平台下 Install.ini 的路径。
+// 参考 UE 的实现：
+// FDesktopPlatformLinux::EnumerateEngineInstallations
+// FDesktopPlatformMac::EnumerateEngineInstallations
+// 两者都使用 FPlatformProcess::ApplicationSettingsDir() 下的 UnrealEngine/Install.ini
+func InstallIniPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("get user home dir: %w", err)
+	}
+
+	if runtime.GOOS == "darwin" {
+		return filepath.Join(home, "Library", "Application Support", "Epic", "UnrealEngine", "Install.ini"), nil
+	}
+
+	return filepath.Join(home, ".config", "Epic", "UnrealEngine", "Install.ini"), nil
+}
+
+// parseInstallations 解析 Install.ini 中 [Installations] section 的内容，每一项都是 GUID 到引擎路径的映射。
+func parseInstallations(r io.Reader) ([]*EngineInfo, error) {
+	file, err := parseIni(r)
+	if err != nil {
+		return nil, err
+	}
+
+	section := file.section(installationsSection)
+	if section == nil {
+		return nil, nil
+	}
+
+	var infos []*EngineInfo
+	for _, e := range section.entries {
+		if len(e.key) == 0 || len(e.value) == 0 {
+			continue
+		}
+
+		infos = append(infos, &EngineInfo{
+			Version:     e.key,
+			InstallPath: filepath.Clean(e.value),
+		})
+	}
+
+	return infos, nil
+}
+
+// readInstallIniEngineInfos 读取 Install.ini 中注册的所有引擎，包括目录已经不存在的引擎。
+func readInstallIniEngineInfos() ([]*EngineInfo, error) {
+	iniPath, err := InstallIniPath()
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(iniPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			core.LogD("install ini %s not found", iniPath)
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("open %s: %w", iniPath, err)
+	}
+	defer f.Close()
+
+	infos, err := parseInstallations(f)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", iniPath, err)
+	}
+
+	for _, info := range infos {
+		core.LogD("find engine info in %s: ver %s path %s", iniPath, info.Version, info.InstallPath)
+	}
+
+	return infos, nil
+}
+
+// findInstallIniEngineInfos 从 Install.ini 中查找所有已注册的引擎，忽略已经不存在的目录。
+func findInstallIniEngineInfos() ([]*EngineInfo, error) {
+	infos, err := readInstallIniEngineInfos()
+	if err != nil {
+		return nil, err
+	}
+
+	var validInfos []*EngineInfo
+	for _, info := range infos {
+		if yes, _ := osutil.IsDir(info.InstallPath); !yes {
+			core.LogD("skip engine %s, directory %s not found", info.Version, info.InstallPath)
+			continue
+		}
+
+		validInfos = append(validInfos, info)
+	}
+
+	return validInfos, nil
+}
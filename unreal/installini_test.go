@@ -0,0 +1,153 @@
+package unreal
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestParseInstallations 测试 parseInstallations 函数。
+func TestParseInstallations(t *testing.T) {
+	cases := []struct {
+		name   string
+		ini    string
+		expect []EngineInfo
+	}{
+		{
+			name:   "empty file",
+			ini:    "",
+			expect: nil,
+		},
+		{
+			name: "no installations section",
+			ini: `[Other]
+Key=Value`,
+			expect: nil,
+		},
+		{
+			name: "installations with other sections",
+			ini: `; comment
+[Other]
+{00000000-0000-0000-0000-000000000000}=/should/be/ignored
+
+[Installations]
+{4A1D5C2E-6F5B-4A34-8D5B-1B2C3D4E5F60}=/opt/UnrealEngine
+  MyBuild = "/home/user/UE_5.3/"
+EmptyPath=
+`,
+			expect: []EngineInfo{
+				{Version: "{4A1D5C2E-6F5B-4A34-8D5B-1B2C3D4E5F60}", InstallPath: filepath.Clean("/opt/UnrealEngine")},
+				{Version: "MyBuild", InstallPath: filepath.Clean("/home/user/UE_5.3")},
+			},
+		},
+		{
+			name: "case insensitive section name",
+			ini: `[installations]
+{4A1D5C2E-6F5B-4A34-8D5B-1B2C3D4E5F60}=/opt/UnrealEngine`,
+			expect: []EngineInfo{
+				{Version: "{4A1D5C2E-6F5B-4A34-8D5B-1B2C3D4E5F60}", InstallPath: filepath.Clean("/opt/UnrealEngine")},
+			},
+		},
+	}
+
+	for i, c := range cases {
+		actual, err := parseInstallations(strings.NewReader(c.ini))
+		if err != nil {
+			t.Errorf("%d:%s: unexpected error: %s", i, c.name, err)
+			continue
+		}
+
+		if len(actual) != len(c.expect) {
+			t.Errorf("%d:%s: expect %d engines, got %d", i, c.name, len(c.expect), len(actual))
+			continue
+		}
+
+		for j, info := range actual {
+			if *info != c.expect[j] {
+				t.Errorf("%d:%s: engine %d:\nexpect:\n%+v\n\nactual:\n%+v", i, c.name, j, c.expect[j], *info)
+			}
+		}
+	}
+}
+
+// TestIniEditor 测试修改 Install.ini 时保留没有修改的内容。
+func TestIniEditor(t *testing.T) {
+	const guid = "{4A1D5C2E-6F5B-4A34-8D5B-1B2C3D4E5F60}"
+	const origin = "; installed engines\r\n" +
+		"Orphan line\r\n" +
+		"[Installations]\r\n" +
+		"; keep me\r\n" +
+		"MyBuild = \"/home/user/UE_5.3/\"\r\n" +
+		"{4a1d5c2e-6f5b-4a34-8d5b-1b2c3d4e5f60}=/old\r\n" +
+		"\r\n" +
+		"[Other]\r\n" +
+		"Key=Value\r\n"
+
+	cases := []struct {
+		name   string
+		ini    string
+		edit   func(e *iniEditor) bool
+		expect string
+	}{
+		{
+			name: "replace",
+			ini:  origin,
+			edit: func(e *iniEditor) bool {
+				e.set(installationsSection, matchGuid(guid), guid, "/new")
+				return true
+			},
+			expect: strings.Replace(origin, "{4a1d5c2e-6f5b-4a34-8d5b-1b2c3d4e5f60}=/old", guid+"=/new", 1),
+		},
+		{
+			name: "append to section",
+			ini:  origin,
+			edit: func(e *iniEditor) bool {
+				e.set(installationsSection, matchGuid("Another"), "Another", "/another")
+				return true
+			},
+			expect: strings.Replace(origin, "/old\r\n", "/old\r\nAnother=/another\r\n", 1),
+		},
+		{
+			name: "create section",
+			ini:  "[Other]\nKey=Value",
+			edit: func(e *iniEditor) bool {
+				e.set(installationsSection, matchGuid(guid), guid, "/new")
+				return true
+			},
+			expect: "[Other]\nKey=Value\n\n[Installations]\n" + guid + "=/new\n",
+		},
+		{
+			name: "create file",
+			ini:  "",
+			edit: func(e *iniEditor) bool {
+				e.set(installationsSection, matchGuid(guid), guid, "/new")
+				return true
+			},
+			expect: "[Installations]\n" + guid + "=/new\n",
+		},
+		{
+			name:   "remove",
+			ini:    origin,
+			edit:   func(e *iniEditor) bool { return e.remove(installationsSection, matchGuid(guid)) },
+			expect: strings.Replace(origin, "{4a1d5c2e-6f5b-4a34-8d5b-1b2c3d4e5f60}=/old\r\n", "", 1),
+		},
+		{
+			name:   "remove missing",
+			ini:    origin,
+			edit:   func(e *iniEditor) bool { return !e.remove("Other", matchGuid(guid)) },
+			expect: origin,
+		},
+	}
+
+	for i, c := range cases {
+		e := newIniEditor(c.ini)
+		if !c.edit(e) {
+			t.Errorf("%d:%s: edit failed", i, c.name)
+			continue
+		}
+
+		if actual := e.String(); actual != c.expect {
+			t.Errorf("%d:%s: expect %q, actual %q", i, c.name, c.expect, actual)
+		}
+	}
+}
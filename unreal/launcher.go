@@ -0,0 +1,207 @@
+package unreal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/zhiruili/urem/core"
+	"github.com/zhiruili/urem/osutil"
+)
+
+// LauncherInstalledPath 是 LauncherInstalled.dat 文件的路径，为空时使用当前平台的默认路径。
+var LauncherInstalledPath string
+
+// launcherEnginePrefix 是 launcher 安装的引擎的 AppName 前缀，如 UE_5.3。
+const launcherEnginePrefix = "UE_"
+
+// LauncherInstallation 是 LauncherInstalled.dat 中的一个安装项。
+type LauncherInstallation struct {
+	InstallLocation string
+	NamespaceId     string
+	ItemId          string
+	ArtifactId      string
+	AppVersion      string
+	AppName         string
+}
+
+// LauncherInstalled 对应 Epic launcher 的 LauncherInstalled.dat 文件，记录了 launcher 安装的引擎、插件等内容。
+type LauncherInstalled struct {
+	InstallationList []*LauncherInstallation
+}
+
+// PluginInstallInfo 是通过 launcher 安装到引擎中的 Marketplace 插件信息。
+type PluginInstallInfo struct {
+	Name        string
+	AppName     string
+	AppVersion  string
+	InstallPath string
+	Engine      *EngineInfo
+}
+
+// DefaultLauncherInstalledPath 获取当前平台下 LauncherInstalled.dat 的默认路径。
+// 参考 UE 的实现：
+// FDesktopPlatformWindows::EnumerateLauncherEngineInstallations
+func DefaultLauncherInstalledPath() (string, error) {
+	if runtime.GOOS == "windows" {
+		programData := os.Getenv("PROGRAMDATA")
+		if len(programData) == 0 {
+			programData = `C:\ProgramData`
+		}
+
+		return filepath.Join(programData, "Epic", "UnrealEngineLauncher", "LauncherInstalled.dat"), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("get user home dir: %w", err)
+	}
+
+	if runtime.GOOS == "darwin" {
+		return filepath.Join(home, "Library", "Application Support", "Epic", "UnrealEngineLauncher", "LauncherInstalled.dat"), nil
+	}
+
+	return filepath.Join(home, ".config", "Epic", "UnrealEngineLauncher", "LauncherInstalled.dat"), nil
+}
+
+func launcherInstalledPath() (string, error) {
+	if len(LauncherInstalledPath) != 0 {
+		return LauncherInstalledPath, nil
+	}
+
+	return DefaultLauncherInstalledPath()
+}
+
+// ReadLauncherInstalled 读取并解析 LauncherInstalled.dat 文件。
+func ReadLauncherInstalled(path string) (*LauncherInstalled, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("open launcher installed file: %w", err)
+	}
+
+	var installed LauncherInstalled
+	if err := json.Unmarshal(content, &installed); err != nil {
+		return nil, fmt.Errorf("unmarshal launcher installed file: %w", err)
+	}
+
+	return &installed, nil
+}
+
+func (inst *LauncherInstallation) isEngine() bool {
+	return strings.HasPrefix(inst.AppName, launcherEnginePrefix)
+}
+
+// EngineInfos 获取 launcher 安装的所有引擎，引擎的标识为 AppName 中的版本号，和 EngineAssociation 一致。
+func (l *LauncherInstalled) EngineInfos() []*EngineInfo {
+	var infos []*EngineInfo
+	for _, inst := range l.InstallationList {
+		if !inst.isEngine() || len(inst.InstallLocation) == 0 {
+			continue
+		}
+
+		infos = append(infos, &EngineInfo{
+			Version:     strings.TrimPrefix(inst.AppName, launcherEnginePrefix),
+			InstallPath: filepath.Clean(inst.InstallLocation),
+		})
+	}
+
+	return infos
+}
+
+// isSubPath 检查 p 是否在 dir 目录之下。
+func isSubPath(dir string, p string) bool {
+	rel, err := filepath.Rel(dir, p)
+	if err != nil {
+		return false
+	}
+
+	return rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// PluginInfos 获取安装到给定引擎中的插件，不在任何一个引擎目录下的安装项会被忽略。
+func (l *LauncherInstalled) PluginInfos(engines []*EngineInfo) []*PluginInstallInfo {
+	var plugins []*PluginInstallInfo
+	for _, inst := range l.InstallationList {
+		if inst.isEngine() || len(inst.InstallLocation) == 0 {
+			continue
+		}
+
+		location := filepath.Clean(inst.InstallLocation)
+		for _, engine := range engines {
+			if !isSubPath(filepath.Join(engine.InstallPath, "Engine", "Plugins"), location) {
+				continue
+			}
+
+			name := inst.AppName
+			if pluginFile, err := osutil.FindFirstFileWithExtension(location, ".uplugin"); err == nil {
+				pluginFileName := filepath.Base(pluginFile)
+				name = strings.TrimSuffix(pluginFileName, filepath.Ext(pluginFileName))
+			}
+
+			plugins = append(plugins, &PluginInstallInfo{
+				Name:        name,
+				AppName:     inst.AppName,
+				AppVersion:  inst.AppVersion,
+				InstallPath: location,
+				Engine:      engine,
+			})
+			break
+		}
+	}
+
+	return plugins
+}
+
+// readDefaultLauncherInstalled 读取 LauncherInstalled.dat，文件不存在时返回 nil。
+func readDefaultLauncherInstalled() (*LauncherInstalled, error) {
+	path, err := launcherInstalledPath()
+	if err != nil {
+		return nil, err
+	}
+
+	installed, err := ReadLauncherInstalled(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			core.LogD("launcher installed file %s not found", path)
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return installed, nil
+}
+
+// findLauncherEngineInfos 从 LauncherInstalled.dat 中查找所有 launcher 安装的引擎。
+func findLauncherEngineInfos() ([]*EngineInfo, error) {
+	installed, err := readDefaultLauncherInstalled()
+	if err != nil || installed == nil {
+		return nil, err
+	}
+
+	infos := installed.EngineInfos()
+	for _, info := range infos {
+		core.LogD("find engine info in launcher installed file: ver %s path %s", info.Version, info.InstallPath)
+	}
+
+	return infos, nil
+}
+
+// FindAllPluginInstallInfos 查找所有通过 launcher 安装到引擎中的 Marketplace 插件。
+func FindAllPluginInstallInfos() ([]*PluginInstallInfo, error) {
+	engines, err := FindAllEngineInfos()
+	if err != nil {
+		return nil, err
+	}
+
+	installed, err := readDefaultLauncherInstalled()
+	if err != nil || installed == nil {
+		return nil, err
+	}
+
+	return installed.PluginInfos(engines), nil
+}
@@ -0,0 +1,38 @@
+package unreal
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// TestReadLauncherInstalled 测试 LauncherInstalled.dat 的解析。
+func TestReadLauncherInstalled(t *testing.T) {
+	installed, err := ReadLauncherInstalled(filepath.Join("..", "testdata", "LauncherInstalled.dat"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(installed.InstallationList) != 3 {
+		t.Fatalf("expect 3 installations, got %d", len(installed.InstallationList))
+	}
+
+	engines := installed.EngineInfos()
+	if len(engines) != 1 {
+		t.Fatalf("expect 1 engine, got %d", len(engines))
+	}
+
+	expectEngine := EngineInfo{Version: "5.3", InstallPath: filepath.Clean("/opt/Epic Games/UE_5.3")}
+	if *engines[0] != expectEngine {
+		t.Errorf("engine:\nexpect:\n%+v\n\nactual:\n%+v", expectEngine, *engines[0])
+	}
+
+	plugins := installed.PluginInfos(engines)
+	if len(plugins) != 1 {
+		t.Fatalf("expect 1 plugin, got %d", len(plugins))
+	}
+
+	plugin := plugins[0]
+	if plugin.Name != "FakeMarketPlugin53" || plugin.AppVersion != "1.2.0-5.3" || plugin.Engine != engines[0] {
+		t.Errorf("unexpected plugin: %+v", *plugin)
+	}
+}
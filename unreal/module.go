@@ -0,0 +1,140 @@
+package unreal
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/zhiruili/urem/core"
+)
+
+// 模块和插件描述文件的后缀。
+const (
+	buildFileSuffix  = ".Build.cs"
+	pluginFileSuffix = ".uplugin"
+)
+
+// sourceFileExts 是 C++ 源文件和头文件的后缀。
+var sourceFileExts = []string{".cpp", ".cc", ".c", ".h", ".hpp", ".inl"}
+
+// IsSourceFile 检查文件是否是 C++ 源文件或头文件。
+func IsSourceFile(name string) bool {
+	return core.StrContains(sourceFileExts, strings.ToLower(filepath.Ext(name)))
+}
+
+// ModuleLocator 通过向上查找 *.Build.cs 和 *.uplugin 文件确定源文件所属的 module 和插件，
+// 会缓存每个目录的查找结果，适合需要定位大量文件的场景。
+type ModuleLocator struct {
+	dirFiles map[string]map[string]string // 目录 -> 后缀 -> 文件名去掉后缀的部分，没有时为空字符串
+}
+
+// NewModuleLocator 创建一个 ModuleLocator。
+func NewModuleLocator() *ModuleLocator {
+	return &ModuleLocator{dirFiles: map[string]map[string]string{}}
+}
+
+// findInDir 查找目录下第一个以 suffix 结尾的文件，返回去掉后缀的文件名。
+func (l *ModuleLocator) findInDir(dir string, suffix string) string {
+	files, ok := l.dirFiles[dir]
+	if !ok {
+		files = map[string]string{}
+		l.dirFiles[dir] = files
+	}
+
+	if name, ok := files[suffix]; ok {
+		return name
+	}
+
+	name := ""
+	if entries, err := os.ReadDir(dir); err == nil {
+		for _, entry := range entries {
+			if !entry.IsDir() && hasSuffixFold(entry.Name(), suffix) {
+				name = entry.Name()[:len(entry.Name())-len(suffix)]
+				break
+			}
+		}
+	}
+
+	files[suffix] = name
+	return name
+}
+
+// findUp 从文件所在目录开始向上查找包含以 suffix 结尾的文件的目录，返回去掉后缀的文件名和目录，找不到时返回空字符串。
+func (l *ModuleLocator) findUp(file string, suffix string) (string, string) {
+	dir := filepath.Dir(filepath.Clean(file))
+	for {
+		if name := l.findInDir(dir, suffix); len(name) != 0 {
+			return name, dir
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", ""
+		}
+
+		dir = parent
+	}
+}
+
+// Module 获取文件所属的 module 的名字和目录，即包含 <Module>.Build.cs 的目录。
+func (l *ModuleLocator) Module(file string) (string, string) {
+	return l.findUp(file, buildFileSuffix)
+}
+
+// Plugin 获取文件所属的插件的名字和目录，即包含 <Plugin>.uplugin 的目录。
+func (l *ModuleLocator) Plugin(file string) (string, string) {
+	return l.findUp(file, pluginFileSuffix)
+}
+
+// hasSuffixFold 检查文件名是否以 suffix 结尾且去掉后缀后不为空，和 Windows 上的 UBT 一样不区分大小写。
+func hasSuffixFold(name string, suffix string) bool {
+	return len(name) > len(suffix) && strings.EqualFold(name[len(name)-len(suffix):], suffix)
+}
+
+// isModuleFile 检查文件是否是 module 的描述文件。
+func isModuleFile(name string) bool {
+	return hasSuffixFold(name, buildFileSuffix)
+}
+
+// ModuleNameOfFile 获取 *.Build.cs 文件对应的 module 名字。
+func ModuleNameOfFile(file string) string {
+	name := filepath.Base(file)
+	return name[:len(name)-len(buildFileSuffix)]
+}
+
+// FindModuleFiles 递归查找目录下所有的 *.Build.cs 文件，跳过隐藏目录和 Binaries、Intermediate 等生成的目录，
+// 目录不存在时返回空列表。
+func FindModuleFiles(dir string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if p == dir {
+				return filepath.SkipDir
+			}
+			return err
+		}
+
+		if d.IsDir() {
+			name := d.Name()
+			if p != dir && (strings.HasPrefix(name, ".") || core.StrContains(buildInputSkipDirs, name)) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if isModuleFile(d.Name()) {
+			files = append(files, p)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(files)
+	return files, nil
+}
@@ -0,0 +1,87 @@
+package unreal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestModuleLocator 测试 ModuleLocator 不区分大小写地查找 *.Build.cs 和 *.uplugin。
+func TestModuleLocator(t *testing.T) {
+	dir := t.TempDir()
+	files := []string{
+		"Source/Game/Game.Build.cs",
+		"Source/Lower/Lower.build.cs",
+		"Source/Lower/Private/A.cpp",
+		"Plugins/Tool/TOOL.UPLUGIN",
+		"Plugins/Tool/Source/ToolCore/ToolCore.BUILD.CS",
+		"Plugins/Tool/Source/ToolCore/Public/B.h",
+		"Source/Game/Private/C.cpp",
+		"Source/D.cpp",
+	}
+
+	for _, f := range files {
+		p := filepath.Join(dir, filepath.FromSlash(f))
+		if err := os.MkdirAll(filepath.Dir(p), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(p, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cases := []struct {
+		file         string
+		expectModule string
+		expectPlugin string
+	}{
+		{file: "Source/Game/Private/C.cpp", expectModule: "Game"},
+		{file: "Source/Lower/Private/A.cpp", expectModule: "Lower"},
+		{file: "Plugins/Tool/Source/ToolCore/Public/B.h", expectModule: "ToolCore", expectPlugin: "TOOL"},
+		{file: "Source/D.cpp"},
+	}
+
+	locator := NewModuleLocator()
+	for i, c := range cases {
+		file := filepath.Join(dir, filepath.FromSlash(c.file))
+		module, _ := locator.Module(file)
+		plugin, _ := locator.Plugin(file)
+		if module != c.expectModule || plugin != c.expectPlugin {
+			t.Errorf("%d:%s: expect (%s, %s), actual (%s, %s)", i, c.file, c.expectModule, c.expectPlugin, module, plugin)
+		}
+	}
+
+	modules, err := FindModuleFiles(filepath.Join(dir, "Source"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(modules) != 2 || ModuleNameOfFile(modules[1]) != "Lower" {
+		t.Errorf("unexpected module files %v", modules)
+	}
+}
+
+// TestIsBuildInputFile 测试 IsBuildInputFile 函数。
+func TestIsBuildInputFile(t *testing.T) {
+	cases := []struct {
+		name   string
+		expect bool
+	}{
+		{name: "Game.Build.cs", expect: true},
+		{name: "Game.build.cs", expect: true},
+		{name: "Game.Target.cs", expect: true},
+		{name: "GameEditor.target.cs", expect: true},
+		{name: "Game.uproject", expect: true},
+		{name: "Tool.UPLUGIN", expect: true},
+		{name: ".Build.cs", expect: false},
+		{name: "Game.cs", expect: false},
+		{name: "Game.cpp", expect: false},
+	}
+
+	for i, c := range cases {
+		if actual := IsBuildInputFile(c.name); actual != c.expect {
+			t.Errorf("%d:%s: expect %t, actual %t", i, c.name, c.expect, actual)
+		}
+	}
+}
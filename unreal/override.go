@@ -0,0 +1,65 @@
+package unreal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/zhiruili/urem/core"
+)
+
+// EngineRootEnv 是用于指定引擎根目录的环境变量名。
+const EngineRootEnv = "UREM_ENGINE_ROOT"
+
+// EngineSource 表示引擎信息的来源。
+type EngineSource string
+
+const (
+	EngineSourceFlag        = EngineSource("--engine flag")                         // 命令行参数
+	EngineSourceEnv         = EngineSource(EngineRootEnv + " environment variable") // 环境变量
+	EngineSourceConfig      = EngineSource(ProjectConfigFileName + " config file")  // 工程配置文件
+	EngineSourceAssociation = EngineSource("EngineAssociation")                     // 工程文件中的 EngineAssociation
+)
+
+// newOverrideEngineInfo 根据指定的引擎根目录创建引擎信息，目录中必须存在 Engine/Build/Build.version。
+func newOverrideEngineInfo(engineRoot string, source EngineSource) (*EngineInfo, error) {
+	absRoot, err := filepath.Abs(engineRoot)
+	if err != nil {
+		return nil, fmt.Errorf("illegal engine root %s from %s", engineRoot, source)
+	}
+
+	if !IsEngineDir(absRoot) {
+		return nil, fmt.Errorf("illegal engine root %s from %s: %s not found", absRoot, source, BuildVersionPath(absRoot))
+	}
+
+	info := &EngineInfo{InstallPath: absRoot, Source: source}
+	fillBuildVersion(info)
+	return info, nil
+}
+
+// findOverrideEngineInfo 按命令行参数、环境变量、工程配置文件的顺序查找指定的引擎根目录，没有指定时返回 nil。
+func findOverrideEngineInfo(projectInfo *ProjectInfo) (*EngineInfo, error) {
+	if len(core.Global.Engine) != 0 {
+		return newOverrideEngineInfo(core.Global.Engine, EngineSourceFlag)
+	}
+
+	if root := os.Getenv(EngineRootEnv); len(root) != 0 {
+		return newOverrideEngineInfo(root, EngineSourceEnv)
+	}
+
+	config, err := projectInfo.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(config.EngineRoot) != 0 {
+		root := config.EngineRoot
+		if !filepath.IsAbs(root) {
+			root = filepath.Join(projectInfo.ProjectDir(), root)
+		}
+
+		return newOverrideEngineInfo(root, EngineSourceConfig)
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,86 @@
+package unreal
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ProjectPluginsDir 获取工程的 Plugins 目录。
+func (pi *ProjectInfo) ProjectPluginsDir() string {
+	return filepath.Join(pi.ProjectDir(), "Plugins")
+}
+
+// EnginePluginsDir 获取引擎的 Engine/Plugins 目录，Marketplace 插件也安装在其中。
+func (info *EngineInfo) EnginePluginsDir() string {
+	return filepath.Join(info.InstallPath, "Engine", "Plugins")
+}
+
+// PluginNameOfFile 获取 .uplugin 文件对应的插件名字。
+func PluginNameOfFile(file string) string {
+	return strings.TrimSuffix(filepath.Base(file), pluginFileSuffix)
+}
+
+// FindPluginFiles 递归查找目录下所有的 .uplugin 文件，和 UE 一样，找到插件后不再查找插件目录内部。
+// 参考 UE 的实现：
+// FPluginManager::FindPluginsInDirectory
+func FindPluginFiles(dir string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if p == dir {
+				// 目录不存在时说明没有插件
+				return filepath.SkipDir
+			}
+			return err
+		}
+
+		if !d.IsDir() {
+			return nil
+		}
+
+		matches, err := filepath.Glob(filepath.Join(p, "*.uplugin"))
+		if err != nil {
+			return err
+		}
+
+		if len(matches) != 0 {
+			files = append(files, matches[0])
+			return filepath.SkipDir
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(files)
+	return files, nil
+}
+
+// FindProjectPluginFiles 查找工程 Plugins 目录和 additionalDirs 中的所有插件，additionalDirs 是工程描述文件中的
+// AdditionalPluginDirectories，相对路径相对于工程目录。
+func (pi *ProjectInfo) FindProjectPluginFiles(additionalDirs []string) ([]string, error) {
+	dirs := []string{pi.ProjectPluginsDir()}
+	for _, dir := range additionalDirs {
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(pi.ProjectDir(), dir)
+		}
+		dirs = append(dirs, dir)
+	}
+
+	var files []string
+	for _, dir := range dirs {
+		found, err := FindPluginFiles(dir)
+		if err != nil {
+			return nil, fmt.Errorf("find plugins in %s: %w", dir, err)
+		}
+		files = append(files, found...)
+	}
+
+	return files, nil
+}
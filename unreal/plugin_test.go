@@ -0,0 +1,42 @@
+package unreal
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestFindProjectPluginFiles 测试查找工程 Plugins 目录和 AdditionalPluginDirectories 中的插件。
+func TestFindProjectPluginFiles(t *testing.T) {
+	dir := t.TempDir()
+	extra := filepath.Join(dir, "Shared")
+	files := []string{
+		filepath.Join(dir, "Game", "Plugins", "A", "A.uplugin"),
+		filepath.Join(dir, "Game", "Plugins", "A", "Nested", "N.uplugin"),
+		filepath.Join(dir, "Game", "Plugins", "Group", "B", "B.uplugin"),
+		filepath.Join(dir, "Game", "Relative", "C", "C.uplugin"),
+		filepath.Join(extra, "D", "D.uplugin"),
+	}
+
+	for _, f := range files {
+		if err := os.MkdirAll(filepath.Dir(f), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(f, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	projectInfo := &ProjectInfo{ProjectFilePath: filepath.Join(dir, "Game", "Game.uproject")}
+	actual, err := projectInfo.FindProjectPluginFiles([]string{"Relative", extra, "Missing"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := []string{files[0], files[2], files[3], files[4]}
+	if !reflect.DeepEqual(actual, expect) {
+		t.Errorf("expect %v, actual %v", expect, actual)
+	}
+}
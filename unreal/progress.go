@@ -0,0 +1,74 @@
+package unreal
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// UBT 在 -progress 参数下输出的进度标记，参考 UBT 的实现 ProgressWriter，格式有以下几种：
+//
+//	@progress push 5%
+//	@progress 'Generating project files...' 45%
+//	@progress pop
+var progressRegexp = regexp.MustCompile(`^@progress\s+'(.*)'\s+(\d+)%$`)
+
+const progressPrefix = "@progress"
+
+// parseProgressLine 解析 UBT 的进度标记，ok 表示这一行是否是进度标记，
+// hasValue 表示这一行是否包含进度信息，push 和 pop 不包含进度信息。
+func parseProgressLine(line string) (message string, percent int, hasValue bool, ok bool) {
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, progressPrefix) {
+		return "", 0, false, false
+	}
+
+	matches := progressRegexp.FindStringSubmatch(line)
+	if matches == nil {
+		return "", 0, false, true
+	}
+
+	percent, err := strconv.Atoi(matches[2])
+	if err != nil {
+		return "", 0, false, true
+	}
+
+	return matches[1], percent, true, true
+}
+
+// isTerminal 检查文件是否是终端，是终端时可以原地刷新进度。
+func isTerminal(f *os.File) bool {
+	stat, err := f.Stat()
+	return err == nil && stat.Mode()&os.ModeCharDevice != 0
+}
+
+// progressPrinter 用于输出 UBT 的进度，在终端中会原地刷新同一行。
+type progressPrinter struct {
+	inPlace bool
+	active  bool
+}
+
+func newProgressPrinter() *progressPrinter {
+	return &progressPrinter{inPlace: isTerminal(os.Stdout)}
+}
+
+// update 输出新的进度。
+func (p *progressPrinter) update(message string, percent int) {
+	text := fmt.Sprintf("[%3d%%] %s", percent, message)
+	if p.inPlace {
+		fmt.Fprintf(os.Stdout, "\r%s\033[K", text)
+		p.active = true
+	} else {
+		fmt.Fprintln(os.Stdout, text)
+	}
+}
+
+// clear 在输出其他内容之前结束当前的进度行。
+func (p *progressPrinter) clear() {
+	if p.active {
+		fmt.Fprintln(os.Stdout)
+		p.active = false
+	}
+}
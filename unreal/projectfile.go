@@ -0,0 +1,153 @@
+package unreal
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// findTopLevelValue 查找 JSON 对象中第一层 key 对应的值在 content 中的范围，找不到时返回 -1。
+func findTopLevelValue(content []byte, key string) (int, int, error) {
+	dec := json.NewDecoder(bytes.NewReader(content))
+	depth := 0
+	expectKey := false
+	for {
+		token, err := dec.Token()
+		if err == io.EOF {
+			return -1, -1, nil
+		}
+		if err != nil {
+			return -1, -1, err
+		}
+
+		if delim, ok := token.(json.Delim); ok {
+			switch delim {
+			case '{':
+				depth++
+				expectKey = depth == 1
+			case '[':
+				depth++
+			case '}', ']':
+				depth--
+				expectKey = depth == 1
+			}
+			continue
+		}
+
+		if depth != 1 {
+			continue
+		}
+
+		if !expectKey {
+			expectKey = true
+			continue
+		}
+
+		expectKey = false
+		if name, ok := token.(string); !ok || name != key {
+			continue
+		}
+
+		// 跳过冒号和空白找到值的起始位置，再读取完整的值得到结束位置
+		start := int(dec.InputOffset())
+		for start < len(content) && bytes.IndexByte([]byte(": \t\r\n"), content[start]) >= 0 {
+			start++
+		}
+
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return -1, -1, err
+		}
+
+		return start, int(dec.InputOffset()), nil
+	}
+}
+
+// detectIndent 检测 JSON 对象第一层使用的缩进，检测不到时使用 tab。
+func detectIndent(content []byte) string {
+	openIdx := bytes.IndexByte(content, '{')
+	if openIdx < 0 {
+		return "\t"
+	}
+
+	rest := content[openIdx+1:]
+	lineStart := bytes.IndexByte(rest, '\n')
+	if lineStart < 0 {
+		return "\t"
+	}
+
+	line := rest[lineStart+1:]
+	n := 0
+	for n < len(line) && (line[n] == ' ' || line[n] == '\t') {
+		n++
+	}
+
+	if n == 0 {
+		return "\t"
+	}
+
+	return string(line[:n])
+}
+
+// SetEngineAssociation 修改工程文件内容中的 EngineAssociation 字段，保持文件原有的格式和字段顺序。
+// 如果字段不存在，就将其插入到对象的第一个字段之前。
+func SetEngineAssociation(content []byte, association string) ([]byte, error) {
+	value, err := json.Marshal(association)
+	if err != nil {
+		return nil, err
+	}
+
+	start, end, err := findTopLevelValue(content, "EngineAssociation")
+	if err != nil {
+		return nil, fmt.Errorf("parse project file: %w", err)
+	}
+
+	var out bytes.Buffer
+	if start >= 0 {
+		out.Write(content[:start])
+		out.Write(value)
+		out.Write(content[end:])
+		return out.Bytes(), nil
+	}
+
+	openIdx := bytes.IndexByte(content, '{')
+	if openIdx < 0 {
+		return nil, fmt.Errorf("parse project file: JSON object not found")
+	}
+
+	indent := detectIndent(content)
+	hasOtherField := len(bytes.TrimSpace(content[openIdx+1:])) > 1
+	out.Write(content[:openIdx+1])
+	out.WriteString("\n" + indent + `"EngineAssociation": `)
+	out.Write(value)
+	if hasOtherField {
+		out.WriteString(",")
+		out.Write(content[openIdx+1:])
+	} else {
+		out.WriteString("\n")
+		out.Write(bytes.TrimLeft(content[openIdx+1:], " \t\r\n"))
+	}
+
+	return out.Bytes(), nil
+}
+
+// UpdateEngineAssociation 修改工程文件中的 EngineAssociation 字段并写回文件。
+func (pi *ProjectInfo) UpdateEngineAssociation(association string) error {
+	content, err := os.ReadFile(pi.ProjectFilePath)
+	if err != nil {
+		return fmt.Errorf("open project file: %w", err)
+	}
+
+	updated, err := SetEngineAssociation(content, association)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(pi.ProjectFilePath, updated, 0644); err != nil {
+		return fmt.Errorf("write project file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package unreal
+
+import "testing"
+
+// TestSetEngineAssociation 测试 SetEngineAssociation 函数。
+func TestSetEngineAssociation(t *testing.T) {
+	cases := []struct {
+		name   string
+		json   string
+		expect string
+	}{
+		{
+			name: "replace version",
+			json: `{
+	"FileVersion": 3,
+	"EngineAssociation": "5.1",
+	"Category": ""
+}`,
+			expect: `{
+	"FileVersion": 3,
+	"EngineAssociation": "{4A1D5C2E-6F5B-4A34-8D5B-1B2C3D4E5F60}",
+	"Category": ""
+}`,
+		},
+		{
+			name: "ignore nested key",
+			json: `{
+	"Plugins": [
+		{
+			"EngineAssociation": "nested"
+		}
+	],
+	"EngineAssociation":"",
+	"Modules": []
+}`,
+			expect: `{
+	"Plugins": [
+		{
+			"EngineAssociation": "nested"
+		}
+	],
+	"EngineAssociation":"{4A1D5C2E-6F5B-4A34-8D5B-1B2C3D4E5F60}",
+	"Modules": []
+}`,
+		},
+		{
+			name: "insert missing key",
+			json: `{
+  "FileVersion": 3
+}`,
+			expect: `{
+  "EngineAssociation": "{4A1D5C2E-6F5B-4A34-8D5B-1B2C3D4E5F60}",
+  "FileVersion": 3
+}`,
+		},
+		{
+			name: "insert into empty object",
+			json: `{}`,
+			expect: `{
+	"EngineAssociation": "{4A1D5C2E-6F5B-4A34-8D5B-1B2C3D4E5F60}"
+}`,
+		},
+	}
+
+	for i, c := range cases {
+		actual, err := SetEngineAssociation([]byte(c.json), "{4A1D5C2E-6F5B-4A34-8D5B-1B2C3D4E5F60}")
+		if err != nil {
+			t.Errorf("%d:%s: unexpected error: %s", i, c.name, err)
+			continue
+		}
+
+		if c.expect != string(actual) {
+			t.Errorf("%d:%s:\nexpect:\n%s\n\nactual:\n%s", i, c.name, c.expect, actual)
+		}
+	}
+}
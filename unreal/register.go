@@ -0,0 +1,268 @@
+package unreal
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/zhiruili/urem/core"
+	"github.com/zhiruili/urem/executor"
+)
+
+// registryBuildsKey 是 Windows 下注册源码引擎使用的注册表项。
+const registryBuildsKey = `Registry::HKEY_CURRENT_USER\SOFTWARE\Epic Games\Unreal Engine\Builds`
+
+// FormatGuid 将 GUID 格式化为 UE 注册引擎时使用的格式，即 EGuidFormats::DigitsWithHyphensInBraces。
+func FormatGuid(guid string) (string, error) {
+	s := normalizeGuid(guid)
+	if len(s) == 0 {
+		return "", fmt.Errorf("illegal GUID: %s", guid)
+	}
+
+	return fmt.Sprintf("{%s-%s-%s-%s-%s}", s[0:8], s[8:12], s[12:16], s[16:20], s[20:32]), nil
+}
+
+// NewEngineGuid 生成一个新的用于注册引擎的 GUID。
+func NewEngineGuid() (string, error) {
+	var bs [16]byte
+	if _, err := rand.Read(bs[:]); err != nil {
+		return "", fmt.Errorf("generate GUID: %w", err)
+	}
+
+	return FormatGuid(fmt.Sprintf("%X", bs[:]))
+}
+
+// RegisterEngine 将引擎目录注册为源码引擎，guid 为空时复用已有的注册项或者生成新的 GUID，返回注册使用的 GUID。
+// 参考 UE 的实现：
+// FDesktopPlatformLinux::RegisterEngineInstallation
+// FDesktopPlatformWindows::RegisterEngineInstallation
+func RegisterEngine(engineDir string, guid string) (string, error) {
+	absDir, err := filepath.Abs(engineDir)
+	if err != nil {
+		return "", core.IllegalArgErrorf("EngineDir", "illegal path %s", engineDir)
+	}
+
+	if !IsEngineDir(absDir) {
+		return "", core.IllegalArgErrorf("EngineDir", "%s not found", BuildVersionPath(absDir))
+	}
+
+	registered, err := findRegisteredEngineInfos()
+	if err != nil {
+		return "", err
+	}
+
+	if len(guid) == 0 {
+		for _, info := range registered {
+			if samePath(info.InstallPath, absDir) && IsGuidAssociation(info.Version) {
+				core.LogD("engine %s already registered as %s", absDir, info.Version)
+				return info.Version, nil
+			}
+		}
+
+		if guid, err = NewEngineGuid(); err != nil {
+			return "", err
+		}
+	} else if guid, err = FormatGuid(guid); err != nil {
+		return "", core.IllegalArgErrorf("GUID", "%s", err.Error())
+	}
+
+	for _, info := range registered {
+		if matchAssociation(info, guid) && !samePath(info.InstallPath, absDir) {
+			core.LogI("replace engine %s registered as %s", info.InstallPath, guid)
+		}
+	}
+
+	if runtime.GOOS == "windows" {
+		err = setRegistryBuild(guid, absDir)
+	} else {
+		err = setInstallIniBuild(guid, absDir)
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return guid, nil
+}
+
+// UnregisterEngine 取消注册源码引擎，target 可以是注册使用的 GUID 或者引擎目录，返回被取消注册的引擎信息。
+func UnregisterEngine(target string) (*EngineInfo, error) {
+	registered, err := findRegisteredEngineInfos()
+	if err != nil {
+		return nil, err
+	}
+
+	var found *EngineInfo
+	for _, info := range registered {
+		if matchAssociation(info, target) {
+			found = info
+			break
+		}
+	}
+
+	if found == nil {
+		if absTarget, err := filepath.Abs(target); err == nil {
+			for _, info := range registered {
+				if samePath(info.InstallPath, absTarget) {
+					found = info
+					break
+				}
+			}
+		}
+	}
+
+	if found == nil {
+		return nil, fmt.Errorf("no registered engine matches %s", target)
+	}
+
+	if runtime.GOOS == "windows" {
+		err = removeRegistryBuild(found.Version)
+	} else {
+		err = removeInstallIniBuild(found.Version)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return found, nil
+}
+
+// findRegisteredEngineInfos 查找所有用户注册的源码引擎，不包含 launcher 安装的引擎。
+func findRegisteredEngineInfos() ([]*EngineInfo, error) {
+	if runtime.GOOS != "windows" {
+		return readInstallIniEngineInfos()
+	}
+
+	sh, err := executor.New(executor.KindPowerShell)
+	if err != nil {
+		return nil, err
+	}
+
+	stdOut, stdErr, err := sh.Execute(fmt.Sprintf(
+		`(Get-ItemProperty "%s").PSObject.Properties | `+
+			`Where-Object { $_.Name -match '^\{.*\}$' } | %%{ Write-Output $_.Name $_.Value }`, registryBuildsKey))
+	if stdErr != "" {
+		core.LogD("%s", stdErr)
+	}
+
+	if err != nil {
+		// 注册表项不存在时说明还没有注册过任何引擎
+		core.LogD("read registered engines: %s", err.Error())
+		return nil, nil
+	}
+
+	lines := strings.Split(strings.TrimSpace(stdOut), "\n")
+	var infos []*EngineInfo
+	for i := 1; i < len(lines); i += 2 {
+		ver := trimPsOutput(lines[i-1])
+		path := trimPsOutput(lines[i])
+		if len(ver) == 0 || len(path) == 0 {
+			continue
+		}
+
+		infos = append(infos, &EngineInfo{Version: ver, InstallPath: path})
+	}
+
+	return infos, nil
+}
+
+func psQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+func executePs(script string) error {
+	sh, err := executor.New(executor.KindPowerShell)
+	if err != nil {
+		return err
+	}
+
+	stdOut, stdErr, err := sh.Execute(script)
+	if stdOut != "" {
+		core.LogD("%s", stdOut)
+	}
+	if stdErr != "" {
+		core.LogE("%s", stdErr)
+	}
+
+	return err
+}
+
+func setRegistryBuild(guid string, engineDir string) error {
+	script := fmt.Sprintf(
+		`New-Item -Path %[1]s -Force -ErrorAction SilentlyContinue | Out-Null; `+
+			`Set-ItemProperty -Path %[1]s -Name %[2]s -Value %[3]s`,
+		psQuote(registryBuildsKey), psQuote(guid), psQuote(filepath.ToSlash(engineDir)))
+	if err := executePs(script); err != nil {
+		return fmt.Errorf("write registry: %w", err)
+	}
+
+	return nil
+}
+
+func removeRegistryBuild(guid string) error {
+	script := fmt.Sprintf(`Remove-ItemProperty -Path %s -Name %s`, psQuote(registryBuildsKey), psQuote(guid))
+	if err := executePs(script); err != nil {
+		return fmt.Errorf("write registry: %w", err)
+	}
+
+	return nil
+}
+
+// updateInstallIni 读取 Install.ini，通过 update 修改后写回，只有修改的行会变化。
+func updateInstallIni(update func(*iniEditor) error) error {
+	iniPath, err := InstallIniPath()
+	if err != nil {
+		return err
+	}
+
+	content, err := os.ReadFile(iniPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("open %s: %w", iniPath, err)
+	}
+
+	editor := newIniEditor(string(content))
+	if err := update(editor); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(iniPath), os.ModePerm); err != nil {
+		return fmt.Errorf("create dir of %s: %w", iniPath, err)
+	}
+
+	core.LogD("write file to %s", iniPath)
+	return os.WriteFile(iniPath, []byte(editor.String()), 0644)
+}
+
+// matchGuid 创建匹配 GUID 的函数，GUID 的大小写和格式可能不同，不是合法的 GUID 时按照不区分大小写的字符串比较。
+func matchGuid(guid string) func(key string) bool {
+	normalized := normalizeGuid(guid)
+	return func(key string) bool {
+		if len(normalized) == 0 {
+			return strings.EqualFold(key, guid)
+		}
+
+		return normalizeGuid(key) == normalized
+	}
+}
+
+func setInstallIniBuild(guid string, engineDir string) error {
+	return updateInstallIni(func(e *iniEditor) error {
+		e.set(installationsSection, matchGuid(guid), guid, engineDir)
+		return nil
+	})
+}
+
+func removeInstallIniBuild(guid string) error {
+	return updateInstallIni(func(e *iniEditor) error {
+		if !e.remove(installationsSection, matchGuid(guid)) {
+			return fmt.Errorf("engine %s not found in Install.ini", guid)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,78 @@
+package unreal
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/zhiruili/urem/core"
+)
+
+// targetFileSuffix 是 UE target 描述文件的后缀。
+const targetFileSuffix = ".Target.cs"
+
+// ProjectSourceDir 获取工程的 Source 目录。
+func (pi *ProjectInfo) ProjectSourceDir() string {
+	return filepath.Join(pi.ProjectDir(), "Source")
+}
+
+// FindTargets 查找工程 Source 目录下所有 *.Target.cs 文件定义的 target 名。
+func (pi *ProjectInfo) FindTargets() ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(pi.ProjectSourceDir(), "*"+targetFileSuffix))
+	if err != nil {
+		return nil, fmt.Errorf("find target files: %w", err)
+	}
+
+	var targets []string
+	for _, file := range files {
+		targets = append(targets, strings.TrimSuffix(filepath.Base(file), targetFileSuffix))
+	}
+
+	sort.Strings(targets)
+	return targets, nil
+}
+
+// ChooseTarget 从工程的所有 target 中选出需要使用的 target，没有指定时优先使用编辑器 target。
+func (pi *ProjectInfo) ChooseTarget(target string) (string, error) {
+	targets, err := pi.FindTargets()
+	if err != nil {
+		return "", err
+	}
+
+	if len(targets) == 0 {
+		return "", fmt.Errorf("no *%s found in %s", targetFileSuffix, pi.ProjectSourceDir())
+	}
+
+	if len(target) != 0 {
+		if !core.StrContains(targets, target) {
+			return "", core.IllegalArgErrorf("Target", "must be oneof: %s", strings.Join(targets, ", "))
+		}
+
+		return target, nil
+	}
+
+	if len(targets) == 1 {
+		return targets[0], nil
+	}
+
+	editorTarget := pi.ProjectName() + "Editor"
+	if core.StrContains(targets, editorTarget) {
+		return editorTarget, nil
+	}
+
+	return "", core.IllegalArgErrorf("Target", "must be set, available targets: %s", strings.Join(targets, ", "))
+}
+
+// HostPlatform 获取当前系统对应的 UE 平台名。
+func HostPlatform() string {
+	switch runtime.GOOS {
+	case "windows":
+		return "Win64"
+	case "darwin":
+		return "Mac"
+	default:
+		return "Linux"
+	}
+}
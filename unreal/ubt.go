@@ -0,0 +1,179 @@
+package unreal
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/zhiruili/urem/core"
+	"github.com/zhiruili/urem/diag"
+	"github.com/zhiruili/urem/executor"
+)
+
+func fileExists(p string) bool {
+	stat, err := os.Stat(p)
+	return err == nil && !stat.IsDir()
+}
+
+// UbtCommand 获取当前平台下调用 Unreal Build Tool 的程序路径和需要放在其他参数之前的参数。
+// 参考 UE 的实现：
+// FDesktopPlatformWindows::RunUnrealBuildTool 中使用的是 Engine/Build/BatchFiles/Build.bat
+// FDesktopPlatformLinux::RunUnrealBuildTool 中使用的是 Engine/Build/BatchFiles/Linux/Build.sh
+// 如果 Build.sh 不存在，就尝试用 dotnet 直接执行 UnrealBuildTool.dll
+func UbtCommand(engineDir string) (string, []string, error) {
+	batchDir := filepath.Join(engineDir, "Engine", "Build", "BatchFiles")
+	var script string
+	switch runtime.GOOS {
+	case "windows":
+		script = filepath.Join(batchDir, "Build.bat")
+	case "darwin":
+		script = filepath.Join(batchDir, "Mac", "Build.sh")
+	default:
+		script = filepath.Join(batchDir, "Linux", "Build.sh")
+	}
+
+	if fileExists(script) {
+		return script, nil, nil
+	}
+
+	dll := filepath.Join(engineDir, "Engine", "Binaries", "DotNET", "UnrealBuildTool", "UnrealBuildTool.dll")
+	if fileExists(dll) {
+		dotnet, err := exec.LookPath("dotnet")
+		if err != nil {
+			return "", nil, fmt.Errorf("find dotnet to run %s: %w", dll, err)
+		}
+
+		return dotnet, []string{dll}, nil
+	}
+
+	return "", nil, fmt.Errorf("UBT not found in engine %s, neither %s nor %s exists", engineDir, script, dll)
+}
+
+// UbtRunner 用于调用 Unreal Build Tool，在执行过程中实时输出 UBT 的日志和进度。
+type UbtRunner struct {
+	EngineDir string
+	LogDir    string               // 不为空时将完整的输出保存到该目录下
+	OnLine    executor.LineHandler // 不为空时每一行输出都会额外交给它处理
+
+	diagParser *diag.Parser
+}
+
+// NewUbtRunner 创建一个 UbtRunner，如果指定了 projectInfo，完整的输出会保存到工程的 Saved/Logs/urem 目录下。
+func NewUbtRunner(engineDir string, projectInfo *ProjectInfo) *UbtRunner {
+	runner := &UbtRunner{EngineDir: engineDir}
+	if projectInfo != nil {
+		runner.LogDir = projectInfo.ProjectLogDir()
+	}
+
+	return runner
+}
+
+// Diagnostics 获取最近一次执行时从输出中解析出的诊断信息。
+func (r *UbtRunner) Diagnostics() []*diag.Diagnostic {
+	if r.diagParser == nil {
+		return nil
+	}
+
+	return r.diagParser.Diagnostics()
+}
+
+// logFailure 在执行失败时输出解析到的错误信息，方便在大量输出中找到失败原因。
+func (r *UbtRunner) logFailure() {
+	diagnostics := r.Diagnostics()
+	errCount := diag.Count(diagnostics, diag.SeverityError)
+	core.LogE("UBT failed with %d error(s), %d warning(s)", errCount, diag.Count(diagnostics, diag.SeverityWarning))
+	for _, d := range diagnostics {
+		if d.Severity == diag.SeverityError {
+			core.LogE("  %s", diag.FormatQuickfixLine(d))
+		}
+	}
+}
+
+// createLogFile 在 LogDir 下创建以时间命名的日志文件。
+func (r *UbtRunner) createLogFile() (*os.File, error) {
+	if err := os.MkdirAll(r.LogDir, os.ModePerm); err != nil {
+		return nil, err
+	}
+
+	name := fmt.Sprintf("UBT-%s.log", time.Now().Format("20060102-150405"))
+	return os.Create(filepath.Join(r.LogDir, name))
+}
+
+// Run 执行 UBT，args 会作为独立的参数传给 UBT。
+func (r *UbtRunner) Run(args ...string) error {
+	bin, prefixArgs, err := UbtCommand(r.EngineDir)
+	if err != nil {
+		return err
+	}
+
+	fullArgs := append(append([]string{bin}, prefixArgs...), args...)
+	cmdLine := strings.Join(fullArgs, " ")
+	core.LogD("command: %s", cmdLine)
+
+	exe, err := executor.New(executor.KindDirect)
+	if err != nil {
+		return err
+	}
+
+	var logFile *os.File
+	if len(r.LogDir) != 0 {
+		if logFile, err = r.createLogFile(); err != nil {
+			core.LogE("create UBT log file in %s: %s", r.LogDir, err.Error())
+		} else {
+			defer logFile.Close()
+			fmt.Fprintf(logFile, "%s\n", cmdLine)
+		}
+	}
+
+	r.diagParser = diag.NewParser()
+	progress := newProgressPrinter()
+	err = exe.Stream(func(line string, isErr bool) {
+		if logFile != nil {
+			fmt.Fprintf(logFile, "%s\n", line)
+		}
+
+		if r.OnLine != nil {
+			r.OnLine(line, isErr)
+		}
+
+		if message, percent, hasValue, ok := parseProgressLine(line); ok {
+			if hasValue {
+				progress.update(message, percent)
+			}
+			return
+		}
+
+		r.diagParser.Feed(line)
+		progress.clear()
+		if isErr {
+			core.LogE("%s", line)
+		} else {
+			core.LogI("%s", line)
+		}
+	}, fullArgs...)
+	progress.clear()
+
+	if err != nil {
+		r.logFailure()
+	}
+
+	if logFile != nil {
+		if err != nil {
+			core.LogE("see full UBT log at %s", logFile.Name())
+		} else {
+			core.LogD("UBT log saved to %s", logFile.Name())
+		}
+	}
+
+	return err
+}
+
+// ExecuteUbt 执行 Unreal Build Tool 的命令，args 会作为独立的参数传给 UBT。
+// 如果指定了 projectInfo，完整的输出会保存到工程的 Saved/Logs/urem 目录下。
+func ExecuteUbt(engineDir string, projectInfo *ProjectInfo, args ...string) error {
+	return NewUbtRunner(engineDir, projectInfo).Run(args...)
+}
@@ -0,0 +1,99 @@
+package unreal
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/zhiruili/urem/executor"
+)
+
+// TestParseProgressLine 测试 parseProgressLine 函数。
+func TestParseProgressLine(t *testing.T) {
+	cases := []struct {
+		name          string
+		line          string
+		expectOK      bool
+		expectValue   bool
+		expectMessage string
+		expectPercent int
+	}{
+		{"normal line", "Discovering modules, targets and source code for project...", false, false, "", 0},
+		{"push", "@progress push 5%", true, false, "", 0},
+		{"pop", "@progress pop", true, false, "", 0},
+		{"value", "@progress 'Generating code completion data...' 45%", true, true, "Generating code completion data...", 45},
+		{"value with spaces", "  @progress 'Writing project files...' 100%\r", true, true, "Writing project files...", 100},
+	}
+
+	for i, c := range cases {
+		message, percent, hasValue, ok := parseProgressLine(c.line)
+		if ok != c.expectOK || hasValue != c.expectValue || message != c.expectMessage || percent != c.expectPercent {
+			t.Errorf("%d:%s: got (%q, %d, %t, %t)", i, c.name, message, percent, hasValue, ok)
+		}
+	}
+}
+
+// TestUbtRunner 使用 executor.Fake 测试 UBT 的调用参数、输出处理和日志文件。
+func TestUbtRunner(t *testing.T) {
+	engineDir := t.TempDir()
+	script := filepath.Join(engineDir, "Engine", "Build", "BatchFiles", "Linux", "Build.sh")
+	switch runtime.GOOS {
+	case "windows":
+		script = filepath.Join(engineDir, "Engine", "Build", "BatchFiles", "Build.bat")
+	case "darwin":
+		script = filepath.Join(engineDir, "Engine", "Build", "BatchFiles", "Mac", "Build.sh")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(script), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(script, nil, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	fake := &executor.Fake{
+		Responses: []*executor.Response{
+			{StdOut: "Generating project files\n@progress push 5%\n@progress 'Writing' 50%\n@progress pop\nDone\n"},
+		},
+	}
+	defer executor.Use(fake)()
+
+	var lines []string
+	logDir := filepath.Join(t.TempDir(), "Logs")
+	runner := &UbtRunner{
+		EngineDir: engineDir,
+		LogDir:    logDir,
+		OnLine: func(line string, isErr bool) {
+			lines = append(lines, line)
+		},
+	}
+
+	if err := runner.Run("-projectfiles", "-project=/path with space/Game.uproject"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expectArgs := []string{script, "-projectfiles", "-project=/path with space/Game.uproject"}
+	if len(fake.Calls) != 1 || strings.Join(fake.Calls[0], "|") != strings.Join(expectArgs, "|") {
+		t.Errorf("unexpected calls: %q", fake.Calls)
+	}
+
+	if len(lines) != 5 {
+		t.Errorf("expect 5 lines, got %q", lines)
+	}
+
+	logs, err := os.ReadDir(logDir)
+	if err != nil || len(logs) != 1 {
+		t.Fatalf("expect 1 log file in %s, got %d, err: %v", logDir, len(logs), err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(logDir, logs[0].Name()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(content), "@progress 'Writing' 50%\n@progress pop\nDone\n") {
+		t.Errorf("unexpected log content:\n%s", content)
+	}
+}
@@ -2,14 +2,14 @@ package unreal
 
 import (
 	"bytes"
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 
 	"github.com/zhiruili/urem/core"
-	"github.com/zhiruili/urem/pwsh"
+	"github.com/zhiruili/urem/executor"
 )
 
 type ProjectInfo struct {
@@ -29,6 +29,11 @@ func (pi *ProjectInfo) ProjectDir() string {
 	return filepath.Dir(pi.ProjectFilePath)
 }
 
+// ProjectLogDir 获取 urem 保存日志的目录，即工程的 Saved/Logs/urem 目录。
+func (pi *ProjectInfo) ProjectLogDir() string {
+	return filepath.Join(pi.ProjectDir(), "Saved", "Logs", "urem")
+}
+
 func (pi *ProjectInfo) ProjectVscodeDir() string {
 	return filepath.Join(pi.ProjectDir(), ".vscode")
 }
@@ -41,57 +46,108 @@ func (pi *ProjectInfo) ProjectClangDbPath() string {
 	return filepath.Join(pi.ProjectVscodeDir(), pi.ProjectClangDbName())
 }
 
-// GetEngineVersion 获取工程所用的引擎版本。
-func (pi *ProjectInfo) GetEngineVersion() (string, error) {
-	content, err := os.ReadFile(pi.ProjectFilePath)
-	if err != nil {
-		return "", fmt.Errorf("open project file: %w", err)
-	}
+// EngineInfo 用于存放 UE 引擎的信息。
+type EngineInfo struct {
+	Version      string        // 引擎的标识，launcher 安装的引擎是版本号，源码引擎是 GUID
+	InstallPath  string        // 引擎的根目录
+	BuildVersion *BuildVersion // 从 Build.version 读取到的版本信息，读取失败时为 nil
+	Source       EngineSource  // 引擎信息的来源，仅在查找工程使用的引擎时设置
+}
 
-	var file uprojectFile
-	err = json.Unmarshal(content, &file)
-	if err != nil {
-		return "", fmt.Errorf("unmarshal project file: %w", err)
+// RealVersion 获取引擎实际的版本号，如果没有 Build.version 信息，就返回引擎的标识。
+func (info *EngineInfo) RealVersion() string {
+	if info.BuildVersion != nil {
+		return info.BuildVersion.String()
 	}
 
-	if len(file.EngineAssociation) == 0 {
-		return "", fmt.Errorf("empty EngineAssociation")
+	return info.Version
+}
+
+func fillBuildVersion(info *EngineInfo) {
+	ver, err := ReadBuildVersion(info.InstallPath)
+	if err != nil {
+		core.LogD("read build version of engine %s: %s", info.InstallPath, err.Error())
+		return
 	}
 
-	return file.EngineAssociation, nil
+	info.BuildVersion = ver
 }
 
-type uprojectFile struct {
-	EngineAssociation string
-	Category          string
-	Description       string
-	Modules           []moduleMetaInfo
-	FileVersion       int
+func trimPsOutput(s string) string {
+	return strings.Trim(strings.TrimSpace(s), "\"")
 }
 
-type moduleMetaInfo struct {
-	Name         string
-	Type         string
-	LoadingPhase string
-}
+// samePath 检查两个路径是否指向同一个位置，Windows 下不区分大小写。
+func samePath(a string, b string) bool {
+	a, b = filepath.Clean(a), filepath.Clean(b)
+	if runtime.GOOS == "windows" {
+		return strings.EqualFold(a, b)
+	}
 
-// EngineInfo 用于存放 UE 引擎的信息。
-type EngineInfo struct {
-	Version     string
-	InstallPath string
+	return a == b
 }
 
-func trimPsOutput(s string) string {
-	return strings.Trim(strings.TrimSpace(s), "\"")
+// appendEngineInfos 将 others 中安装路径还未出现过的引擎加入 infos 中。
+func appendEngineInfos(infos []*EngineInfo, others []*EngineInfo) []*EngineInfo {
+	for _, other := range others {
+		existed := false
+		for _, info := range infos {
+			if samePath(info.InstallPath, other.InstallPath) {
+				existed = true
+				break
+			}
+		}
+
+		if !existed {
+			infos = append(infos, other)
+		}
+	}
+
+	return infos
 }
 
 // FindAllEngineInfos 查找所有已安装的引擎信息。
-// 需要根据不同平台参考 UE 自己的实现，这里先只处理 Windows 的情况，参考 UE 的实现：
-// FDesktopPlatformWindows::EnumerateEngineInstallations
+// 根据运行时的平台选择查找方式，Windows 读取注册表，其他平台读取 Install.ini，
+// 之后再补充 LauncherInstalled.dat 中记录的引擎。
 func FindAllEngineInfos() ([]*EngineInfo, error) {
-	sh := pwsh.New()
-	var stdOut, stdErr string
+	var infos []*EngineInfo
 	var err error
+	if runtime.GOOS == "windows" {
+		infos, err = findRegistryEngineInfos()
+	} else {
+		infos, err = findInstallIniEngineInfos()
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	launcherInfos, err := findLauncherEngineInfos()
+	if err != nil {
+		core.LogE("find launcher installed engines: %s", err.Error())
+	}
+
+	infos = appendEngineInfos(infos, launcherInfos)
+	if len(infos) == 0 {
+		return nil, fmt.Errorf("unreal engine not found")
+	}
+
+	for _, info := range infos {
+		fillBuildVersion(info)
+	}
+
+	return infos, nil
+}
+
+// findRegistryEngineInfos 从 Windows 注册表中查找所有已安装的引擎信息，参考 UE 的实现：
+// FDesktopPlatformWindows::EnumerateEngineInstallations
+func findRegistryEngineInfos() ([]*EngineInfo, error) {
+	sh, err := executor.New(executor.KindPowerShell)
+	if err != nil {
+		return nil, err
+	}
+
+	var stdOut, stdErr string
 	var cmdResultList []string
 
 	stdOut, stdErr, err = sh.Execute(
@@ -126,10 +182,6 @@ func FindAllEngineInfos() ([]*EngineInfo, error) {
 	core.LogD("find engine info 2 stdOut:\n%s", stdOut)
 	cmdResultList = append(cmdResultList, strings.Split(stdOut, "\n")...)
 
-	if len(cmdResultList) <= 1 {
-		return nil, fmt.Errorf("unreal engine not found")
-	}
-
 	var infos []*EngineInfo
 	for i := 1; i < len(cmdResultList); i += 2 {
 		ver := trimPsOutput(cmdResultList[i-1])
@@ -150,6 +202,8 @@ func FindAllEngineInfos() ([]*EngineInfo, error) {
 }
 
 // FindEngineInfo 获取特定版本的引擎的路径。如果不指定 version，则返回找到的第一个版本的信息。
+// version 可以是版本号或者源码引擎的 GUID，如果版本号没有精确匹配的引擎，
+// 会再根据 Build.version 中的 Major.Minor 版本号查找。
 func FindEngineInfo(version string) (*EngineInfo, error) {
 	infos, err := FindAllEngineInfos()
 	if err != nil {
@@ -161,11 +215,20 @@ func FindEngineInfo(version string) (*EngineInfo, error) {
 	}
 
 	for _, info := range infos {
-		if info.Version == version {
+		if matchAssociation(info, version) {
 			return info, nil
 		}
 	}
 
+	if !IsGuidAssociation(version) {
+		for _, info := range infos {
+			if info.BuildVersion != nil && info.BuildVersion.MajorMinor() == version {
+				core.LogD("engine %s matches version %s by build version", info.InstallPath, version)
+				return info, nil
+			}
+		}
+	}
+
 	if core.Global.Verbose {
 		var buf bytes.Buffer
 		for i, info := range infos {
@@ -184,37 +247,40 @@ func FindEngineInfo(version string) (*EngineInfo, error) {
 	return nil, fmt.Errorf("engine with version '%s' no found", version)
 }
 
-// ExecuteUbt 执行 Unreal Build Tool 的命令。
-func ExecuteUbt(engineDir string, args string) error {
-	sh := pwsh.New()
-
-	// 参考 UE 的实现：
-	// FDesktopPlatformWindows::RunUnrealBuildTool
-	// 这个函数中使用的路径是：Engine/Build/BatchFiles/Build.bat
-	binPath := filepath.Join(engineDir, "Engine", "Build", "BatchFiles", "Build.bat")
-	pwCmd := fmt.Sprintf("& \"%s\" %s", binPath, args)
-	stdOut, stdErr, err := sh.Execute(pwCmd)
-	if stdOut != "" {
-		core.LogD("%s", stdOut)
+// LoadClangdFlags 读取 clangd_args 模板中的参数，每行一个参数，忽略空行。
+// ref: https://github.com/natsu-anon/ue-assist/
+func LoadClangdFlags() ([]string, error) {
+	bs, err := core.Global.EmbedFs.ReadFile("resources/compile/clangd_args.tmpl")
+	if err != nil {
+		return nil, fmt.Errorf("load clangd_args file template: %w", err)
 	}
-	if stdErr != "" {
-		core.LogE("%s", stdErr)
+
+	var flags []string
+	for _, line := range strings.Split(string(bs), "\n") {
+		if line = strings.TrimSpace(line); len(line) != 0 {
+			flags = append(flags, line)
+		}
 	}
 
-	return err
+	return flags, nil
 }
 
 // GenerateClangdFlagsFile 生成 clangd_args 文件，用于指定 clangd 的额外参数。
-// ref: https://github.com/natsu-anon/ue-assist/
-func GenerateClangdFlagsFile(projectDir string) (string, error) {
-	bs, err := core.Global.EmbedFs.ReadFile("resources/compile/clangd_args.tmpl")
-	if err != nil {
-		return "", fmt.Errorf("load clangd_args file template: %w", err)
+func GenerateClangdFlagsFile(projectDir string, flags []string) (string, error) {
+	outDir := filepath.Join(projectDir, ".vscode")
+	if err := os.MkdirAll(outDir, os.ModePerm); err != nil {
+		return "", fmt.Errorf("create dir %s: %w", outDir, err)
+	}
+
+	var buf bytes.Buffer
+	for _, flag := range flags {
+		buf.WriteString(flag)
+		buf.WriteString("\n")
 	}
 
-	outFile := filepath.Join(projectDir, ".vscode", "clangd_args")
+	outFile := filepath.Join(outDir, "clangd_args")
 	core.LogD("write file to %s", outFile)
-	return outFile, os.WriteFile(outFile, bs, 0644)
+	return outFile, os.WriteFile(outFile, buf.Bytes(), 0644)
 }
 
 // ExecuteUbtGenProject 执行 Unreal Build Tool 的工程构建命令。
@@ -226,8 +292,8 @@ func ExecuteUbtGenProject(engineDir string, projectInfo *ProjectInfo) error {
 	projectName := projectInfo.ProjectName()
 	core.LogD("detect project name %s", projectName)
 
-	args := fmt.Sprintf("-projectfiles -project='%s' -game -engine -progress", projectInfo.ProjectFilePath)
-	if err := ExecuteUbt(engineDir, args); err != nil {
+	args := []string{"-projectfiles", "-project=" + projectInfo.ProjectFilePath, "-game", "-engine", "-progress"}
+	if err := ExecuteUbt(engineDir, projectInfo, args...); err != nil {
 		return fmt.Errorf("execute UBT: %w", err)
 	}
 
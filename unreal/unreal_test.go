@@ -0,0 +1,64 @@
+package unreal
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/zhiruili/urem/executor"
+)
+
+// TestFindRegistryEngineInfos 使用 executor.Fake 测试从注册表查找引擎的逻辑。
+func TestFindRegistryEngineInfos(t *testing.T) {
+	fake := &executor.Fake{
+		Responses: []*executor.Response{
+			{
+				Match:  "HKEY_LOCAL_MACHINE",
+				StdOut: "5.3\r\nC:\\Program Files\\Epic Games\\UE_5.3\r\n5.1\r\n\"C:\\Program Files\\Epic Games\\UE_5.1\"\r\n",
+			},
+			{
+				Match:  "HKEY_CURRENT_USER",
+				StdOut: "{4A1D5C2E-6F5B-4A34-8D5B-1B2C3D4E5F60}\r\nD:/UnrealEngine\r\n",
+			},
+		},
+	}
+	defer executor.Use(fake)()
+
+	infos, err := findRegistryEngineInfos()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expect := []EngineInfo{
+		{Version: "5.3", InstallPath: `C:\Program Files\Epic Games\UE_5.3`},
+		{Version: "5.1", InstallPath: `C:\Program Files\Epic Games\UE_5.1`},
+		{Version: "{4A1D5C2E-6F5B-4A34-8D5B-1B2C3D4E5F60}", InstallPath: "D:/UnrealEngine"},
+	}
+
+	if len(infos) != len(expect) {
+		t.Fatalf("expect %d engines, got %d", len(expect), len(infos))
+	}
+
+	for i, info := range infos {
+		if *info != expect[i] {
+			t.Errorf("engine %d:\nexpect:\n%+v\n\nactual:\n%+v", i, expect[i], *info)
+		}
+	}
+
+	if len(fake.Calls) != 2 {
+		t.Errorf("expect 2 PowerShell calls, got %d", len(fake.Calls))
+	}
+}
+
+// TestFindRegistryEngineInfosError 测试执行 PowerShell 失败时返回错误。
+func TestFindRegistryEngineInfosError(t *testing.T) {
+	fake := &executor.Fake{
+		Responses: []*executor.Response{
+			{StdErr: "access denied", Err: errors.New("exit status 1")},
+		},
+	}
+	defer executor.Use(fake)()
+
+	if _, err := findRegistryEngineInfos(); err == nil {
+		t.Errorf("expect error when PowerShell failed")
+	}
+}
@@ -0,0 +1,131 @@
+package watchcmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/zhiruili/urem/core"
+	"github.com/zhiruili/urem/gencmd"
+	"github.com/zhiruili/urem/osutil"
+	"github.com/zhiruili/urem/unreal"
+)
+
+// maxReportedChanges 是状态行中最多列出的变化数量。
+const maxReportedChanges = 5
+
+// Cmd 是监视工程文件变化并自动重新生成工程的命令。
+type Cmd struct {
+	Interval    time.Duration `arg:"--interval" help:"interval to poll the file system" default:"1s"`
+	Debounce    time.Duration `arg:"--debounce" help:"wait until no change happens for this duration before regenerating" default:"2s"`
+	Vs          bool          `arg:"--vs" help:"also regenerate the Visual Studio project files"`
+	ProjectFile string        `arg:"positional,required"`
+}
+
+// logStatus 输出带有时间的状态行。
+func logStatus(f string, a ...interface{}) {
+	core.LogI("[%s] %s", time.Now().Format("15:04:05"), fmt.Sprintf(f, a...))
+}
+
+// formatChanges 将变化格式化为状态行中的描述，路径相对于工程目录。
+func formatChanges(projectDir string, changes []fileChange) string {
+	var parts []string
+	for i, c := range changes {
+		if i == maxReportedChanges {
+			parts = append(parts, fmt.Sprintf("and %d more", len(changes)-maxReportedChanges))
+			break
+		}
+
+		p := c.path
+		if rel, err := filepath.Rel(projectDir, p); err == nil {
+			p = filepath.ToSlash(rel)
+		}
+
+		parts = append(parts, fmt.Sprintf("%s %s", c.kind, p))
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// regenerate 重新生成 clang database，如果指定了 --vs，还会重新生成 VS 工程。
+func (cmd *Cmd) regenerate(projectInfo *unreal.ProjectInfo, changes []fileChange) {
+	logStatus("regenerate for %s", formatChanges(projectInfo.ProjectDir(), changes))
+	start := time.Now()
+	if cmd.Vs {
+		vsCmd := &gencmd.GenVsCmd{ProjectFile: projectInfo.ProjectFilePath}
+		if err := vsCmd.Run(); err != nil {
+			logStatus("generate VS project files failed: %s", err.Error())
+			return
+		}
+	}
+
+	// 源文件的新增和删除不会让 gen clang 的缓存失效，所以总是强制调用 UBT
+	clangCmd := &gencmd.GenClangCmd{ProjectFile: projectInfo.ProjectFilePath, Force: true}
+	if err := clangCmd.Run(); err != nil {
+		logStatus("generate clang database failed: %s", err.Error())
+		return
+	}
+
+	logStatus("regenerate done in %s", time.Since(start).Round(time.Millisecond))
+}
+
+func (cmd *Cmd) watch(projectFilePath string) error {
+	projectInfo := &unreal.ProjectInfo{ProjectFilePath: projectFilePath}
+	last, err := takeSnapshot(projectInfo)
+	if err != nil {
+		return fmt.Errorf("scan project files: %w", err)
+	}
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+
+	ticker := time.NewTicker(cmd.Interval)
+	defer ticker.Stop()
+
+	logStatus("watching %d files of %s, press Ctrl+C to stop", len(last), projectInfo.ProjectName())
+	var pending []fileChange
+	var lastChangeTime time.Time
+	for {
+		select {
+		case <-interrupt:
+			logStatus("stop watching")
+			return nil
+		case <-ticker.C:
+		}
+
+		cur, err := takeSnapshot(projectInfo)
+		if err != nil {
+			core.LogE("scan project files: %s", err.Error())
+			continue
+		}
+
+		if changes := diffSnapshots(last, cur); len(changes) != 0 {
+			core.LogD("detect changes: %s", formatChanges(projectInfo.ProjectDir(), changes))
+			pending = mergeChanges(pending, changes)
+			lastChangeTime = time.Now()
+			last = cur
+		}
+
+		if len(pending) == 0 || time.Since(lastChangeTime) < cmd.Debounce {
+			continue
+		}
+
+		cmd.regenerate(projectInfo, pending)
+		pending = nil
+
+		// 生成过程中的变化会在下一次轮询时发现
+	}
+}
+
+// Run 执行监视操作，直到收到中断信号。
+func (cmd *Cmd) Run() error {
+	if cmd.Interval <= 0 {
+		return core.IllegalArgErrorf("Interval", "must be positive")
+	}
+
+	return osutil.DoInProjectRoot(cmd.ProjectFile, cmd.watch)
+}
@@ -0,0 +1,132 @@
+package watchcmd
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/zhiruili/urem/core"
+	"github.com/zhiruili/urem/unreal"
+)
+
+// sourceExts 是需要关注新增和删除的源文件后缀。
+var sourceExts = []string{".cpp", ".cc", ".c", ".h", ".hpp", ".inl"}
+
+// 文件变化的类型。
+const (
+	changeAdded    = "added"
+	changeRemoved  = "removed"
+	changeModified = "modified"
+)
+
+// fileState 是文件在某一时刻的状态，用于轮询时判断文件是否被修改。
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// snapshot 记录工程中需要关注的所有文件的状态，key 是文件的绝对路径。
+type snapshot map[string]fileState
+
+// fileChange 是一个文件的变化。
+type fileChange struct {
+	kind string
+	path string
+}
+
+func (c fileChange) String() string {
+	return fmt.Sprintf("%s %s", c.kind, c.path)
+}
+
+// isWatchedFile 检查文件是否需要关注。
+func isWatchedFile(name string) bool {
+	return unreal.IsBuildInputFile(name) || core.StrContains(sourceExts, strings.ToLower(filepath.Ext(name)))
+}
+
+// takeSnapshot 获取工程文件以及 Source 和 Plugins 目录下需要关注的文件的状态。
+func takeSnapshot(projectInfo *unreal.ProjectInfo) (snapshot, error) {
+	snap := snapshot{}
+	err := projectInfo.WalkProjectFiles(func(p string, d fs.DirEntry) error {
+		if !isWatchedFile(d.Name()) {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			// 遍历过程中文件可能被删除
+			return nil
+		}
+
+		snap[p] = fileState{modTime: info.ModTime(), size: info.Size()}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if info, err := os.Stat(projectInfo.ProjectFilePath); err == nil {
+		snap[projectInfo.ProjectFilePath] = fileState{modTime: info.ModTime(), size: info.Size()}
+	}
+
+	return snap, nil
+}
+
+// diffSnapshots 比较两次的状态，返回需要重新生成工程的变化：构建输入文件的任何变化，以及源文件的新增和删除。
+func diffSnapshots(prev snapshot, cur snapshot) []fileChange {
+	var changes []fileChange
+	for p, state := range cur {
+		prevState, ok := prev[p]
+		if !ok {
+			changes = append(changes, fileChange{kind: changeAdded, path: p})
+		} else if unreal.IsBuildInputFile(filepath.Base(p)) && prevState != state {
+			changes = append(changes, fileChange{kind: changeModified, path: p})
+		}
+	}
+
+	for p := range prev {
+		if _, ok := cur[p]; !ok {
+			changes = append(changes, fileChange{kind: changeRemoved, path: p})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].path < changes[j].path
+	})
+
+	return changes
+}
+
+// mergeChanges 将新的变化合并到等待处理的变化中，同一个文件只保留一项。
+func mergeChanges(pending []fileChange, changes []fileChange) []fileChange {
+	for _, c := range changes {
+		merged := false
+		for i := range pending {
+			if pending[i].path != c.path {
+				continue
+			}
+
+			merged = true
+			switch {
+			case pending[i].kind == changeAdded && c.kind == changeRemoved:
+				// 新增后又删除，相当于没有变化
+				pending = append(pending[:i], pending[i+1:]...)
+			case pending[i].kind == changeRemoved && c.kind == changeAdded:
+				pending[i].kind = changeModified
+			case pending[i].kind != changeAdded:
+				pending[i].kind = c.kind
+			}
+			break
+		}
+
+		if !merged {
+			pending = append(pending, c)
+		}
+	}
+
+	return pending
+}
@@ -0,0 +1,82 @@
+package watchcmd
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestDiffSnapshots 测试 diffSnapshots 只报告需要重新生成工程的变化。
+func TestDiffSnapshots(t *testing.T) {
+	t0 := time.Unix(1000, 0)
+	t1 := time.Unix(2000, 0)
+	prev := snapshot{
+		"/p/Game.uproject":                  {modTime: t0, size: 10},
+		"/p/Source/Game/Game.Build.cs":      {modTime: t0, size: 10},
+		"/p/Source/Game/Private/Game.cpp":   {modTime: t0, size: 10},
+		"/p/Source/Game/Private/Old.cpp":    {modTime: t0, size: 10},
+		"/p/Plugins/Tools/Tools.uplugin":    {modTime: t0, size: 10},
+		"/p/Source/Game/Public/Unchanged.h": {modTime: t0, size: 10},
+	}
+
+	cur := snapshot{
+		"/p/Game.uproject":                  {modTime: t0, size: 10},
+		"/p/Source/Game/Game.Build.cs":      {modTime: t1, size: 12},
+		"/p/Source/Game/Private/Game.cpp":   {modTime: t1, size: 20},
+		"/p/Source/Game/Private/New.cpp":    {modTime: t1, size: 10},
+		"/p/Plugins/Tools/Tools.uplugin":    {modTime: t0, size: 10},
+		"/p/Source/Game/Public/Unchanged.h": {modTime: t0, size: 10},
+	}
+
+	expect := []fileChange{
+		{kind: changeModified, path: "/p/Source/Game/Game.Build.cs"},
+		{kind: changeAdded, path: "/p/Source/Game/Private/New.cpp"},
+		{kind: changeRemoved, path: "/p/Source/Game/Private/Old.cpp"},
+	}
+
+	if actual := diffSnapshots(prev, cur); !reflect.DeepEqual(actual, expect) {
+		t.Errorf("expect %v, actual %v", expect, actual)
+	}
+}
+
+// TestMergeChanges 测试 mergeChanges 合并同一个文件的多次变化。
+func TestMergeChanges(t *testing.T) {
+	cases := []struct {
+		name    string
+		pending []fileChange
+		changes []fileChange
+		expect  []fileChange
+	}{
+		{
+			name:    "different files",
+			pending: []fileChange{{kind: changeAdded, path: "a"}},
+			changes: []fileChange{{kind: changeModified, path: "b"}},
+			expect:  []fileChange{{kind: changeAdded, path: "a"}, {kind: changeModified, path: "b"}},
+		},
+		{
+			name:    "added then modified",
+			pending: []fileChange{{kind: changeAdded, path: "a"}},
+			changes: []fileChange{{kind: changeModified, path: "a"}},
+			expect:  []fileChange{{kind: changeAdded, path: "a"}},
+		},
+		{
+			name:    "added then removed",
+			pending: []fileChange{{kind: changeAdded, path: "a"}},
+			changes: []fileChange{{kind: changeRemoved, path: "a"}},
+			expect:  []fileChange{},
+		},
+		{
+			name:    "removed then added",
+			pending: []fileChange{{kind: changeRemoved, path: "a"}},
+			changes: []fileChange{{kind: changeAdded, path: "a"}},
+			expect:  []fileChange{{kind: changeModified, path: "a"}},
+		},
+	}
+
+	for i, c := range cases {
+		actual := mergeChanges(c.pending, c.changes)
+		if len(actual) != len(c.expect) || (len(actual) != 0 && !reflect.DeepEqual(actual, c.expect)) {
+			t.Errorf("%d:%s: expect %v, actual %v", i, c.name, c.expect, actual)
+		}
+	}
+}